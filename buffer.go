@@ -5,6 +5,7 @@ import "fmt"
 var (
 	defaultCursor = rune('█')
 	errorRune     = rune('#')
+	wideRune      = rune(0) // second column of wide rune
 )
 
 type Buffer [][]rune
@@ -17,6 +18,9 @@ func (b *Buffer) Drawer(row, col uint, r rune) {
 		(*b)[row] = append((*b)[row], errorRune)
 	}
 	(*b)[row][col] = r
+	if runeWidth(r) == 2 {
+		b.Drawer(row, col+1, wideRune)
+	}
 }
 
 func (b *Buffer) Cursor(row, col uint) {
@@ -29,6 +33,9 @@ func (b Buffer) String() string {
 	for r := range b {
 		str += fmt.Sprintf("%09d|", r+1)
 		for c := range b[r] {
+			if b[r][c] == wideRune {
+				continue
+			}
 			str += string(b[r][c])
 		}
 		if width := len(b[r]); w < width {
@@ -45,6 +52,9 @@ func (b Buffer) Text() string {
 	var str string
 	for r := range b {
 		for c := range b[r] {
+			if b[r][c] == wideRune {
+				continue
+			}
 			str += string(b[r][c])
 		}
 		str += "\n"
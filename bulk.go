@@ -0,0 +1,79 @@
+package tf
+
+// filter return runes accepted by Filter
+func (t *TextField) filter(text []rune) []rune {
+	if t.Filter == nil {
+		return text
+	}
+	filtered := make([]rune, 0, len(text))
+	for _, r := range text {
+		if t.Filter(r) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// clampRange return valid range of text
+func (t *TextField) clampRange(from, to int) (int, int) {
+	if to < from {
+		from, to = to, from
+	}
+	if from < 0 {
+		from = 0
+	}
+	if len(t.text) < to {
+		to = len(t.text)
+	}
+	if to < from {
+		to = from
+	}
+	return from, to
+}
+
+// InsertString insert runes instead of selection or at cursor position
+// by one change. Runes are filtered by Filter.
+func (t *TextField) InsertString(text []rune) {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	text = t.filter(text)
+	from, to, ok := t.GetSelection()
+	if !ok {
+		from, to = t.cursor, t.cursor
+	}
+	t.selection.active = false
+	if from == to && len(text) == 0 {
+		return
+	}
+	t.replace(from, to, text, from+len(text), false)
+}
+
+// DeleteRange remove runes text[from:to].
+func (t *TextField) DeleteRange(from, to int) {
+	t.ReplaceRange(from, to, nil)
+}
+
+// ReplaceRange replace runes text[from:to] by runes filtered by Filter.
+// Cursor inside of range is moved after inserted runes.
+func (t *TextField) ReplaceRange(from, to int, text []rune) {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	text = t.filter(text)
+	from, to = t.clampRange(from, to)
+	if from == to && len(text) == 0 {
+		return
+	}
+	t.selection.active = false
+	cursor := t.cursor
+	switch {
+	case to <= cursor:
+		cursor += len(text) - (to - from)
+	case from < cursor:
+		cursor = from + len(text)
+	}
+	t.replace(from, to, text, cursor, false)
+}
@@ -0,0 +1,168 @@
+package tf
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Clipboard is storage of copied text
+type Clipboard interface {
+	Set(text []rune) error
+	Get() ([]rune, error)
+}
+
+// MemoryClipboard is clipboard in memory of application. By default all
+// fields use one MemoryClipboard.
+type MemoryClipboard struct {
+	mutex sync.Mutex
+	text  []rune
+}
+
+// Set implements Clipboard.
+func (c *MemoryClipboard) Set(text []rune) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.text = append([]rune{}, text...)
+	return nil
+}
+
+// Get implements Clipboard.
+func (c *MemoryClipboard) Get() ([]rune, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]rune{}, c.text...), nil
+}
+
+// OSC52Clipboard set system clipboard by OSC 52 terminal escape sequence.
+// Terminal is not asked for clipboard, so Get return last copied text.
+type OSC52Clipboard struct {
+	Writer io.Writer // terminal output, os.Stdout if nil
+	memory MemoryClipboard
+}
+
+// Set implements Clipboard.
+func (c *OSC52Clipboard) Set(text []rune) error {
+	if err := c.memory.Set(text); err != nil {
+		return err
+	}
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(string(text))) + "\a"
+	w := c.Writer
+	if w == nil {
+		w = os.Stdout
+	}
+	_, err := io.WriteString(w, seq)
+	return err
+}
+
+// Get implements Clipboard.
+func (c *OSC52Clipboard) Get() ([]rune, error) {
+	return c.memory.Get()
+}
+
+// CommandClipboard use external commands, for example xclip or wl-copy.
+type CommandClipboard struct {
+	Copy  []string // command with arguments, text is written in stdin
+	Paste []string // command with arguments, text is read from stdout
+
+	// Exec run command. If Exec is nil, then command is run by os/exec.
+	Exec func(name string, args []string, stdin string) (stdout string, err error)
+}
+
+// XClipClipboard return clipboard for X11 by xclip.
+func XClipClipboard() *CommandClipboard {
+	return &CommandClipboard{
+		Copy:  []string{"xclip", "-selection", "clipboard", "-in"},
+		Paste: []string{"xclip", "-selection", "clipboard", "-out"},
+	}
+}
+
+// WaylandClipboard return clipboard for Wayland by wl-copy and wl-paste.
+func WaylandClipboard() *CommandClipboard {
+	return &CommandClipboard{
+		Copy:  []string{"wl-copy"},
+		Paste: []string{"wl-paste", "--no-newline"},
+	}
+}
+
+func (c *CommandClipboard) run(command []string, stdin string) (string, error) {
+	if len(command) == 0 {
+		return "", fmt.Errorf("clipboard command is empty")
+	}
+	if c.Exec != nil {
+		return c.Exec(command[0], command[1:], stdin)
+	}
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = strings.NewReader(stdin)
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// Set implements Clipboard.
+func (c *CommandClipboard) Set(text []rune) error {
+	_, err := c.run(c.Copy, string(text))
+	return err
+}
+
+// Get implements Clipboard.
+func (c *CommandClipboard) Get() ([]rune, error) {
+	out, err := c.run(c.Paste, "")
+	return []rune(out), err
+}
+
+// defaultClipboard is common clipboard of fields
+var defaultClipboard = new(MemoryClipboard)
+
+// SetClipboard set clipboard of field. For common in-memory clipboard
+// use nil.
+func (t *TextField) SetClipboard(c Clipboard) {
+	t.clipboard = c
+}
+
+func (t *TextField) getClipboard() Clipboard {
+	if t.clipboard == nil {
+		return defaultClipboard
+	}
+	return t.clipboard
+}
+
+// Copy selected text to clipboard. Text of masked field is not copied.
+func (t *TextField) Copy() error {
+	text := t.GetSelectedText()
+	if text == nil {
+		return nil
+	}
+	return t.getClipboard().Set(text)
+}
+
+// Cut selected text to clipboard. Text of masked field is not cut.
+func (t *TextField) Cut() error {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	text := t.GetSelectedText()
+	if text == nil {
+		return nil
+	}
+	if err := t.getClipboard().Set(text); err != nil {
+		return err
+	}
+	t.deleteSelection()
+	return nil
+}
+
+// Paste text from clipboard instead of selection. Runes are filtered by
+// Filter and text is limited by constraints.
+func (t *TextField) Paste() error {
+	text, err := t.getClipboard().Get()
+	if err != nil {
+		return err
+	}
+	t.InsertString(text)
+	return nil
+}
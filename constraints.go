@@ -0,0 +1,103 @@
+package tf
+
+// Constraints of text enforced on each change with inserted runes.
+// Zero value of limit is unlimited.
+type Constraints struct {
+	MaxRunes      int // amount of runes
+	MaxGraphemes  int // amount of grapheme clusters
+	MaxLines      int // amount of lines between '\n' runes
+	MaxLineLength int // amount of runes in line without '\n'
+
+	// Truncate inserted text by constraints instead of rejecting.
+	// Typed runes are always rejected.
+	Truncate bool
+}
+
+// SetConstraints set constraints of text. Current text is not changed.
+func (t *TextField) SetConstraints(c Constraints) {
+	t.constraints = c
+}
+
+// GetConstraints return constraints of text.
+func (t TextField) GetConstraints() Constraints {
+	return t.constraints
+}
+
+// valid return true if text satisfies constraints
+func (c Constraints) valid(text []rune) bool {
+	if 0 < c.MaxRunes && c.MaxRunes < len(text) {
+		return false
+	}
+	if 0 < c.MaxGraphemes && c.MaxGraphemes < len(text) {
+		count := 0
+		for i := 0; i < len(text); i = clusterEnd(text, i) {
+			count++
+		}
+		if c.MaxGraphemes < count {
+			return false
+		}
+	}
+	if 0 < c.MaxLines || 0 < c.MaxLineLength {
+		lines, length := 1, 0
+		for _, r := range text {
+			if r == '\n' {
+				lines++
+				length = 0
+				continue
+			}
+			length++
+			if 0 < c.MaxLineLength && c.MaxLineLength < length {
+				return false
+			}
+		}
+		if 0 < c.MaxLines && c.MaxLines < lines {
+			return false
+		}
+	}
+	return true
+}
+
+// constrain change inserted runes for constraints. Result is false, if
+// change is rejected.
+func (t *TextField) constrain(from, to int, ins *[]rune, cursor *int, typed bool) bool {
+	c := t.constraints
+	if c.MaxRunes == 0 && c.MaxGraphemes == 0 && c.MaxLines == 0 && c.MaxLineLength == 0 {
+		return true
+	}
+	if len(*ins) == 0 || c.valid(t.spliced(from, to, *ins)) {
+		return true
+	}
+	if typed || !c.Truncate {
+		return false
+	}
+	// find longest valid part of inserted runes
+	low, high := 0, len(*ins)
+	for low < high {
+		mid := (low + high + 1) / 2
+		if c.valid(t.spliced(from, to, (*ins)[:mid])) {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	// inserted runes are truncated on border of grapheme cluster
+	size := 0
+	for next := 0; next <= low; next = clusterEnd(*ins, next) {
+		size = next
+		if next == len(*ins) {
+			break
+		}
+	}
+	if !c.valid(t.spliced(from, to, (*ins)[:size])) {
+		return false
+	}
+	switch {
+	case from+len(*ins) <= *cursor:
+		// cursor after inserted runes is moved by dropped runes
+		*cursor -= len(*ins) - size
+	case from+size < *cursor:
+		*cursor = from + size
+	}
+	*ins = (*ins)[:size]
+	return true
+}
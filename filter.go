@@ -0,0 +1,78 @@
+package tf
+
+import (
+	"regexp"
+	"unicode"
+)
+
+func Hex(r rune) (insert bool) {
+	if UnsignedInteger(r) {
+		return true
+	}
+	return ('a' <= r && r <= 'f') || ('A' <= r && r <= 'F')
+}
+
+func Octal(r rune) (insert bool) {
+	return '0' <= r && r <= '7'
+}
+
+func Binary(r rune) (insert bool) {
+	return r == '0' || r == '1'
+}
+
+// Identifier accept runes of Go identifier
+func Identifier(r rune) (insert bool) {
+	return isIdentRune(r)
+}
+
+func ASCIIPrintable(r rune) (insert bool) {
+	return ' ' <= r && r <= '~'
+}
+
+func NoWhitespace(r rune) (insert bool) {
+	return !unicode.IsSpace(r)
+}
+
+// SingleLine reject new line rune
+func SingleLine(r rune) (insert bool) {
+	return r != '\n'
+}
+
+// FilterFromRegexp return filter of runes matched by regular expression,
+// for example `[a-z]`.
+func FilterFromRegexp(re *regexp.Regexp) func(r rune) (insert bool) {
+	return func(r rune) bool {
+		return re.MatchString(string(r))
+	}
+}
+
+// AnyOf return filter of runes accepted by at least one of filters.
+func AnyOf(filters ...func(r rune) bool) func(r rune) (insert bool) {
+	return func(r rune) bool {
+		for _, f := range filters {
+			if f(r) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AllOf return filter of runes accepted by all filters.
+func AllOf(filters ...func(r rune) bool) func(r rune) (insert bool) {
+	return func(r rune) bool {
+		for _, f := range filters {
+			if !f(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Not return filter of runes rejected by filter.
+func Not(filter func(r rune) bool) func(r rune) (insert bool) {
+	return func(r rune) bool {
+		return !filter(r)
+	}
+}
@@ -0,0 +1,227 @@
+package tf
+
+import "unicode"
+
+// Grapheme cluster segmentation is based on UAX #29 "Unicode Text
+// Segmentation" with approximated property tables. Prepend runes are
+// not supported.
+
+const (
+	zwj  = '\u200d' // zero width joiner
+	vs16 = '\ufe0f' // emoji presentation selector
+)
+
+// pictographic is approximation of Extended_Pictographic property.
+var pictographic = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00a9, Hi: 0x00a9, Stride: 1},
+		{Lo: 0x00ae, Hi: 0x00ae, Stride: 1},
+		{Lo: 0x203c, Hi: 0x203c, Stride: 1},
+		{Lo: 0x2049, Hi: 0x2049, Stride: 1},
+		{Lo: 0x2122, Hi: 0x2122, Stride: 1},
+		{Lo: 0x2139, Hi: 0x2139, Stride: 1},
+		{Lo: 0x2194, Hi: 0x2199, Stride: 1},
+		{Lo: 0x21a9, Hi: 0x21aa, Stride: 1},
+		{Lo: 0x231a, Hi: 0x231b, Stride: 1},
+		{Lo: 0x2328, Hi: 0x2328, Stride: 1},
+		{Lo: 0x23cf, Hi: 0x23cf, Stride: 1},
+		{Lo: 0x23e9, Hi: 0x23f3, Stride: 1},
+		{Lo: 0x23f8, Hi: 0x23fa, Stride: 1},
+		{Lo: 0x24c2, Hi: 0x24c2, Stride: 1},
+		{Lo: 0x25aa, Hi: 0x25ab, Stride: 1},
+		{Lo: 0x25b6, Hi: 0x25b6, Stride: 1},
+		{Lo: 0x25c0, Hi: 0x25c0, Stride: 1},
+		{Lo: 0x25fb, Hi: 0x25fe, Stride: 1},
+		{Lo: 0x2600, Hi: 0x27bf, Stride: 1},
+		{Lo: 0x2934, Hi: 0x2935, Stride: 1},
+		{Lo: 0x2b05, Hi: 0x2b07, Stride: 1},
+		{Lo: 0x2b1b, Hi: 0x2b1c, Stride: 1},
+		{Lo: 0x2b50, Hi: 0x2b50, Stride: 1},
+		{Lo: 0x2b55, Hi: 0x2b55, Stride: 1},
+		{Lo: 0x3030, Hi: 0x3030, Stride: 1},
+		{Lo: 0x303d, Hi: 0x303d, Stride: 1},
+		{Lo: 0x3297, Hi: 0x3297, Stride: 1},
+		{Lo: 0x3299, Hi: 0x3299, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f000, Hi: 0x1f1e5, Stride: 1},
+		{Lo: 0x1f200, Hi: 0x1f3fa, Stride: 1},
+		{Lo: 0x1f400, Hi: 0x1faff, Stride: 1},
+		{Lo: 0x1fc00, Hi: 0x1fffd, Stride: 1},
+	},
+}
+
+// simpleRune return true for runes from ranges without combining
+// and wide runes: Latin, Greek, Cyrillic, Armenian.
+func simpleRune(r rune) bool {
+	return r < 0x300 || (0x370 <= r && r < 0x483) || (0x48a <= r && r < 0x591)
+}
+
+func isPictographic(r rune) bool {
+	return unicode.Is(pictographic, r)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return 0x1f1e6 <= r && r <= 0x1f1ff
+}
+
+func isExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me) ||
+		r == '\u200c' || r == zwj ||
+		(0x1f3fb <= r && r <= 0x1f3ff) || // emoji modifiers
+		(0xe0020 <= r && r <= 0xe007f) // tags
+}
+
+func isControl(r rune) bool {
+	if r == '\u200c' || r == zwj || (0xe0020 <= r && r <= 0xe007f) {
+		return false
+	}
+	return unicode.In(r, unicode.Cc, unicode.Zl, unicode.Zp, unicode.Cf)
+}
+
+type hangul uint8
+
+const (
+	hangulNone hangul = iota // 0
+	hangulL                  // 1
+	hangulV                  // 2
+	hangulT                  // 3
+	hangulLV                 // 4
+	hangulLVT                // 5
+)
+
+func hangulType(r rune) hangul {
+	switch {
+	case 0x1100 <= r && r <= 0x115f, 0xa960 <= r && r <= 0xa97c:
+		return hangulL
+	case 0x1160 <= r && r <= 0x11a7, 0xd7b0 <= r && r <= 0xd7c6:
+		return hangulV
+	case 0x11a8 <= r && r <= 0x11ff, 0xd7cb <= r && r <= 0xd7fb:
+		return hangulT
+	case 0xac00 <= r && r <= 0xd7a3:
+		if (r-0xac00)%28 == 0 {
+			return hangulLV
+		}
+		return hangulLVT
+	}
+	return hangulNone
+}
+
+// joinHangul return true, if runes of Hangul syllable sequence
+// must not be separated.
+func joinHangul(prev, next rune) bool {
+	p, n := hangulType(prev), hangulType(next)
+	switch p {
+	case hangulL:
+		return n == hangulL || n == hangulV || n == hangulLV || n == hangulLVT
+	case hangulLV, hangulV:
+		return n == hangulV || n == hangulT
+	case hangulLVT, hangulT:
+		return n == hangulT
+	}
+	return false
+}
+
+// clusterEnd return position after the end of grapheme cluster
+// started at position `pos`.
+func clusterEnd(text []rune, pos int) int {
+	if len(text) <= pos {
+		return len(text)
+	}
+	prev := text[pos]
+	ri := 0 // amount of regional indicators in sequence
+	if isRegionalIndicator(prev) {
+		ri = 1
+	}
+	// pictographic with extends before
+	pict := (prev == 0xa9 || prev == 0xae || !simpleRune(prev)) && isPictographic(prev)
+	for i := pos + 1; i < len(text); i++ {
+		r := text[i]
+		if simpleRune(r) && r != 0xa9 && r != 0xae && (prev != '\r' || r != '\n') {
+			// fast path: rune cannot be part of previous cluster
+			return i
+		}
+		switch {
+		case prev == '\r' && r == '\n':
+		case isControl(prev) || isControl(r):
+			return i
+		case joinHangul(prev, r):
+		case isExtend(r):
+		case unicode.Is(unicode.Mc, r):
+		case prev == zwj && pict && isPictographic(r):
+		case isRegionalIndicator(prev) && isRegionalIndicator(r) && ri%2 == 1:
+		default:
+			return i
+		}
+		if isRegionalIndicator(r) {
+			ri++
+		} else {
+			ri = 0
+		}
+		if isPictographic(r) {
+			pict = true
+		} else if !isExtend(r) {
+			pict = false
+		}
+		prev = r
+	}
+	return len(text)
+}
+
+// clusterWidth return amount of columns for grapheme cluster.
+func clusterWidth(cluster []rune) uint8 {
+	w := runeWidth(cluster[0])
+	if len(cluster) == 2 && isRegionalIndicator(cluster[0]) {
+		return 2 // flag
+	}
+	for _, r := range cluster[1:] {
+		if r == vs16 && isPictographic(cluster[0]) {
+			return 2
+		}
+		if rw := runeWidth(r); w < rw {
+			w = rw
+		}
+	}
+	return w
+}
+
+// clusterStart return position of begin of grapheme cluster with rune
+// at position `pos`.
+func (t *TextField) clusterStart(pos int) int {
+	if pos <= 0 || len(t.text) <= pos {
+		return pos
+	}
+	start := t.lineStart(pos)
+	for end := clusterEnd(t.text, start); end <= pos; end = clusterEnd(t.text, start) {
+		start = end
+	}
+	return start
+}
+
+// clusterBefore return position of begin of grapheme cluster before
+// position `pos`.
+func (t *TextField) clusterBefore(pos int) int {
+	if pos <= 0 {
+		return 0
+	}
+	return t.clusterStart(pos - 1)
+}
+
+// clusterAlign return nearest begin of grapheme cluster at right side of
+// position `pos`.
+func (t *TextField) clusterAlign(pos int) int {
+	if start := t.clusterStart(pos); start != pos {
+		return clusterEnd(t.text, start)
+	}
+	return pos
+}
+
+// renderClusterEnd return position after the end of grapheme cluster started
+// at render position `pos`.
+func (t *TextField) renderClusterEnd(pos int) int {
+	end := pos + 1
+	for end < len(t.render) && t.render[end].t == extend {
+		end++
+	}
+	return end
+}
@@ -0,0 +1,174 @@
+package tf
+
+// TokenKind is kind of token for highlighting
+type TokenKind uint8
+
+const (
+	TokenPlain TokenKind = iota
+	TokenKeyword
+	TokenBuiltin
+	TokenString
+	TokenNumber
+	TokenComment
+	TokenOperator
+	TokenKey
+	TokenSection
+)
+
+// Token is part line[From:To] of line
+type Token struct {
+	From, To int
+	Kind     TokenKind
+}
+
+// Lexer split text into tokens line by line. State is value at the end
+// of previous line, for example inside of multiline comment. State of
+// first line is zero.
+type Lexer interface {
+	Lex(line []rune, state int) (tokens []Token, next int)
+}
+
+// Theme is styles of token kinds
+type Theme map[TokenKind]Style
+
+// DefaultTheme return theme with ANSI colors.
+func DefaultTheme() Theme {
+	return Theme{
+		TokenKeyword:  {Attr: Bold, Fg: PaletteColor(4)},
+		TokenBuiltin:  {Fg: PaletteColor(6)},
+		TokenString:   {Fg: PaletteColor(2)},
+		TokenNumber:   {Fg: PaletteColor(5)},
+		TokenComment:  {Attr: Italic, Fg: PaletteColor(8)},
+		TokenOperator: {Fg: PaletteColor(3)},
+		TokenKey:      {Fg: PaletteColor(4)},
+		TokenSection:  {Attr: Bold, Fg: PaletteColor(5)},
+	}
+}
+
+// Highlighter add styles of tokens to TextField. Only changed lines are
+// lexed again.
+type Highlighter struct {
+	Lexer Lexer
+	Theme Theme // if nil, then DefaultTheme is used
+
+	lines []highlightLine
+	theme Theme
+}
+
+type highlightLine struct {
+	text   []rune
+	state  int // state at begin of line
+	next   int // state at end of line
+	tokens []Token
+}
+
+// SetHighlighter set highlighter of text. For disable highlighting use nil.
+func (t *TextField) SetHighlighter(h *Highlighter) {
+	t.highlighter = h
+}
+
+// Tokens return tokens of each line for text.
+func (h *Highlighter) Tokens(text []rune) [][]Token {
+	h.update(text)
+	tokens := make([][]Token, len(h.lines))
+	for i := range h.lines {
+		tokens[i] = h.lines[i].tokens
+	}
+	return tokens
+}
+
+// update lex changed lines of text. Unchanged lines are found from the
+// begin and from the end of cache, so inserting or removing lines does
+// not invalidate lines after it.
+func (h *Highlighter) update(text []rune) {
+	if h.Lexer == nil {
+		h.lines = nil
+		return
+	}
+	count := 1
+	for _, r := range text {
+		if r == '\n' {
+			count++
+		}
+	}
+	old := h.lines
+	lines := make([]highlightLine, 0, count)
+	state, begin := 0, 0
+	for p := 0; p <= len(text); p++ {
+		if p < len(text) && text[p] != '\n' {
+			continue
+		}
+		line := text[begin:p]
+		index := len(lines)
+		c, ok := cached(old, line, state, index, index-count+len(old))
+		if !ok {
+			tokens, next := h.Lexer.Lex(line, state)
+			c = highlightLine{
+				text:   append([]rune{}, line...),
+				state:  state,
+				next:   next,
+				tokens: tokens,
+			}
+		}
+		lines = append(lines, c)
+		state = c.next
+		begin = p + 1
+	}
+	h.lines = lines
+}
+
+// cached return line from cache at one of indexes with same text and
+// state at begin of line.
+func cached(old []highlightLine, line []rune, state int, indexes ...int) (
+	highlightLine, bool) {
+	for _, i := range indexes {
+		if i < 0 || len(old) <= i {
+			continue
+		}
+		if c := old[i]; c.state == state && equalRunes(c.text, line) {
+			return c, true
+		}
+	}
+	return highlightLine{}, false
+}
+
+func equalRunes(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// spans return style spans of highlighted text.
+func (h *Highlighter) spans(text []rune) []Span {
+	h.update(text)
+	theme := h.Theme
+	if theme == nil {
+		if h.theme == nil {
+			h.theme = DefaultTheme()
+		}
+		theme = h.theme
+	}
+	var spans []Span
+	offset := 0
+	for _, line := range h.lines {
+		for _, tok := range line.tokens {
+			style, ok := theme[tok.Kind]
+			if !ok || tok.Kind == TokenPlain || tok.To <= tok.From {
+				continue
+			}
+			spans = append(spans, Span{
+				From:  offset + tok.From,
+				To:    offset + tok.To,
+				Style: style,
+			})
+		}
+		offset += len(line.text) + 1
+	}
+	return spans
+}
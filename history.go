@@ -0,0 +1,188 @@
+package tf
+
+import "time"
+
+// defaultHistoryLimit is amount of undo steps by default.
+const defaultHistoryLimit = 100
+
+// change is one reversible modification of text.
+type change struct {
+	at       int    // position of modification in text
+	old, new []rune // removed and inserted runes
+	before   int    // cursor position before change
+	after    int    // cursor position after change
+	typed    bool   // change is typed rune and may be coalesced
+}
+
+type history struct {
+	changes []change
+	pos     int  // amount of applied changes
+	saved   int  // position of save point, negative if lost
+	limit   uint // maximal amount of changes
+}
+
+// replace runes text[from:to] by `ins`, store change in history and
+// update buffer. Cursor is moved to position `cursor`. Result is false,
+// if change is rejected.
+func (t *TextField) replace(from, to int, ins []rune, cursor int, typed bool) (ok bool) {
+	if t.inputMask != nil && !t.conform(&from, &to, &ins, &cursor, typed) {
+		return false
+	}
+	if !t.constrain(from, to, &ins, &cursor, typed) {
+		return false
+	}
+	if t.rejected(from, to, ins) {
+		return false
+	}
+	if t.masked() {
+		// secret text is not stored in history
+		t.forgetHistory()
+		t.splice(from, to, ins, cursor)
+		return true
+	}
+	c := change{
+		at:     from,
+		old:    append([]rune{}, t.text[from:to]...),
+		new:    append([]rune{}, ins...),
+		before: t.cursor,
+		after:  cursor,
+		typed:  typed,
+	}
+	t.splice(from, to, c.new, cursor)
+	t.record(c)
+	return true
+}
+
+// splice replace runes text[from:to] by `ins` without history.
+func (t *TextField) splice(from, to int, ins []rune, cursor int) {
+	text := t.spliced(from, to, ins)
+	if t.masked() {
+		// old copy of secret text is not kept in memory
+		zeroRunes(t.text)
+		t.mask.until = time.Time{}
+	}
+	t.text = text
+	t.spans = moveSpans(t.spans, from, to, len(ins))
+	t.state.edits++
+	t.cursor = cursor
+	t.state.changedContent = true
+	if t.state.init {
+		t.updateWidth()
+	}
+}
+
+// spliced return new text with runes text[from:to] replaced by ins.
+func (t *TextField) spliced(from, to int, ins []rune) []rune {
+	text := make([]rune, 0, len(t.text)-(to-from)+len(ins))
+	text = append(text, t.text[:from]...)
+	text = append(text, ins...)
+	return append(text, t.text[to:]...)
+}
+
+func (t *TextField) record(c change) {
+	h := &t.history
+	if h.pos < len(h.changes) {
+		// remove redo changes
+		h.changes = h.changes[:h.pos]
+		if h.pos < h.saved {
+			h.saved = -1
+		}
+	}
+	if last := len(h.changes) - 1; 0 <= last && h.saved != h.pos &&
+		c.typed && h.changes[last].typed && len(c.old) == 0 &&
+		h.changes[last].after == c.before &&
+		h.changes[last].at+len(h.changes[last].new) == c.at &&
+		!wordBreak(h.changes[last].new, c.new) {
+		// coalesce typed runes
+		h.changes[last].new = append(h.changes[last].new, c.new...)
+		h.changes[last].after = c.after
+		return
+	}
+	h.changes = append(h.changes, c)
+	h.pos++
+	limit := int(h.limit)
+	if limit == 0 {
+		limit = defaultHistoryLimit
+	}
+	if remove := len(h.changes) - limit; 0 < remove {
+		h.changes = append(h.changes[:0], h.changes[remove:]...)
+		h.pos -= remove
+		h.saved -= remove
+	}
+	if h.saved < 0 {
+		h.saved = -1
+	}
+}
+
+// wordBreak return true, if typed runes `next` must not be coalesced
+// with typed runes `prev`.
+func wordBreak(prev, next []rune) bool {
+	if len(prev) == 0 || len(next) == 0 {
+		return true
+	}
+	p, n := prev[len(prev)-1], next[0]
+	if n == '\n' || p == '\n' {
+		return true
+	}
+	return n == ' ' && p != ' '
+}
+
+// SetHistoryLimit set maximal amount of undo steps.
+// If steps is zero, then default limit is used.
+func (t *TextField) SetHistoryLimit(steps uint) {
+	t.history.limit = steps
+}
+
+// Undo revert last modification of text and restore cursor position.
+// Return false, if nothing to undo.
+func (t *TextField) Undo() bool {
+	h := &t.history
+	if h.pos == 0 {
+		return false
+	}
+	h.pos--
+	c := h.changes[h.pos]
+	t.selection.active = false
+	t.splice(c.at, c.at+len(c.new), c.old, c.before)
+	return true
+}
+
+// Redo repeat last reverted modification of text.
+// Return false, if nothing to redo.
+func (t *TextField) Redo() bool {
+	h := &t.history
+	if len(h.changes) <= h.pos {
+		return false
+	}
+	c := h.changes[h.pos]
+	h.pos++
+	t.selection.active = false
+	t.splice(c.at, c.at+len(c.old), c.new, c.after)
+	return true
+}
+
+// ResetHistory remove all undo and redo steps and mark text as saved.
+func (t *TextField) ResetHistory() {
+	t.history = history{limit: t.history.limit}
+}
+
+// forgetHistory overwrite runes of history by zero and remove all undo
+// and redo steps. Text is marked as modified.
+func (t *TextField) forgetHistory() {
+	for _, c := range t.history.changes {
+		zeroRunes(c.old)
+		zeroRunes(c.new)
+	}
+	t.ResetHistory()
+	t.history.saved = -1
+}
+
+// MarkSaved store save point of text.
+func (t *TextField) MarkSaved() {
+	t.history.saved = t.history.pos
+}
+
+// Modified return true, if text is changed after save point.
+func (t *TextField) Modified() bool {
+	return t.history.pos != t.history.saved
+}
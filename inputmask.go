@@ -0,0 +1,181 @@
+package tf
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// slot of input mask template
+type slot struct {
+	literal rune // literal rune, zero for input slot
+	accept  func(r rune) bool
+}
+
+// SetInputMask set template of text, for example "9999-99-99" or
+// "(999) 999-9999". Template runes are:
+//
+//	9 - digit
+//	a - letter
+//	* - letter or digit
+//	\ - next rune is literal
+//
+// Other runes are literals. Literals are inserted automatically and
+// skipped by cursor. Current text is conformed to template.
+// For disable input mask use empty template.
+func (t *TextField) SetInputMask(template string) error {
+	var slots []slot
+	escape, inputs := false, 0
+	for _, r := range template {
+		if escape {
+			slots = append(slots, slot{literal: r})
+			escape = false
+			continue
+		}
+		var accept func(r rune) bool
+		switch r {
+		case '\\':
+			escape = true
+			continue
+		case '9':
+			accept = isDigit
+		case 'a':
+			accept = unicode.IsLetter
+		case '*':
+			accept = func(r rune) bool {
+				return unicode.IsLetter(r) || unicode.IsDigit(r)
+			}
+		default:
+			slots = append(slots, slot{literal: r})
+			continue
+		}
+		slots = append(slots, slot{accept: accept})
+		inputs++
+	}
+	if escape {
+		return fmt.Errorf("input mask %q: escape rune at the end", template)
+	}
+	if template != "" && inputs == 0 {
+		return fmt.Errorf("input mask %q: no input slots", template)
+	}
+	t.inputMask = slots
+	if t.inputMask != nil && len(t.text) != 0 {
+		t.replace(0, len(t.text), append([]rune{}, t.text...), len(t.text), false)
+	}
+	return nil
+}
+
+// GetRawText return text without literals of input mask. If input mask
+// is not set, then result is same as GetText.
+func (t TextField) GetRawText() []rune {
+	if t.inputMask == nil {
+		return t.text
+	}
+	raw, _ := t.parseInputMask(t.text)
+	return raw
+}
+
+// parseInputMask return runes of text for input slots and amount of
+// runes not acceptable by slots.
+func (t *TextField) parseInputMask(text []rune) (raw []rune, dropped int) {
+	slots := t.inputMask
+	k := 0
+	for _, r := range text {
+		literal := false
+		for k < len(slots) && slots[k].literal != 0 {
+			literal = slots[k].literal == r
+			k++
+			if literal {
+				break
+			}
+		}
+		if literal {
+			continue
+		}
+		if k < len(slots) && slots[k].accept(r) {
+			raw = append(raw, r)
+			k++
+			continue
+		}
+		dropped++
+	}
+	return
+}
+
+// formatInputMask return text with literals for runes of input slots.
+// Literals after last rune are added until next input slot.
+func (t *TextField) formatInputMask(raw []rune) []rune {
+	if len(raw) == 0 {
+		return nil
+	}
+	var text []rune
+	k := 0
+	for _, s := range t.inputMask {
+		if s.literal != 0 {
+			text = append(text, s.literal)
+			continue
+		}
+		if k == len(raw) {
+			break
+		}
+		text = append(text, raw[k])
+		k++
+	}
+	return text
+}
+
+// literalAt return true if rune at position is literal of input mask
+func (t *TextField) literalAt(pos int) bool {
+	return t.inputMask != nil && pos < len(t.text) && pos < len(t.inputMask) &&
+		t.inputMask[pos].literal != 0
+}
+
+// conform change replacing of text[from:to] by ins for keeping text by
+// input mask. Result is false, if text is not changed.
+func (t *TextField) conform(from, to *int, ins *[]rune, cursor *int, typed bool) bool {
+	for {
+		text := t.spliced(*from, *to, *ins)
+		raw, dropped := t.parseInputMask(text)
+		if typed && 0 < dropped {
+			// rune is not acceptable
+			return false
+		}
+		before, _ := t.parseInputMask(text[:*cursor])
+		text = t.formatInputMask(raw)
+		if equalRunes(text, t.text) && *from < *to && len(*ins) == 0 && 0 < *from {
+			// only literals are removed, so remove rune before
+			*from--
+			*cursor = *from
+			continue
+		}
+		// cursor is placed before input slot
+		pos, count := len(text), 0
+		for i := range text {
+			if t.inputMask[i].literal != 0 {
+				continue
+			}
+			if count == len(before) {
+				pos = i
+				break
+			}
+			count++
+		}
+		// replace only changed part of text
+		prefix := 0
+		for prefix < len(text) && prefix < len(t.text) && text[prefix] == t.text[prefix] {
+			prefix++
+		}
+		suffix := 0
+		for suffix < len(text)-prefix && suffix < len(t.text)-prefix &&
+			text[len(text)-1-suffix] == t.text[len(t.text)-1-suffix] {
+			suffix++
+		}
+		*from, *to = prefix, len(t.text)-suffix
+		*ins = text[prefix : len(text)-suffix]
+		*cursor = pos
+		if *from == *to && len(*ins) == 0 {
+			t.cursor = pos
+			return false
+		}
+		return true
+	}
+}
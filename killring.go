@@ -0,0 +1,222 @@
+package tf
+
+import "unicode"
+
+// killRingSize is maximal amount of killed texts
+const killRingSize = 60
+
+// killRing store killed texts for yanking
+type killRing struct {
+	ring   [][]rune
+	active bool // last command is kill or yank
+	yank   bool // last command is yank
+	edits  uint // amount of edits after last command
+	cursor int  // cursor after last command
+	from   int  // begin of yanked text
+	index  int  // index of yanked text in ring
+}
+
+// last remember state after kill or yank
+func (t *TextField) lastKill(yank bool) {
+	t.kill.active = true
+	t.kill.yank = yank
+	t.kill.edits = t.state.edits
+	t.kill.cursor = t.cursor
+}
+
+// continued return true if there are no other commands after last kill
+// or yank
+func (t *TextField) continued() bool {
+	return t.kill.active && t.kill.edits == t.state.edits && t.kill.cursor == t.cursor
+}
+
+// killRange remove runes text[from:to] and store them in kill ring.
+// Consecutive kills are joined. Runes of masked field are not stored.
+func (t *TextField) killRange(from, to int, backward bool) {
+	if to <= from {
+		return
+	}
+	if t.masked() {
+		t.replace(from, to, nil, from, false)
+		return
+	}
+	text := append([]rune{}, t.text[from:to]...)
+	join := t.continued() && !t.kill.yank && 0 < len(t.kill.ring)
+	if !t.replace(from, to, nil, from, false) {
+		return
+	}
+	k := &t.kill
+	switch {
+	case join && backward:
+		k.ring[len(k.ring)-1] = append(text, k.ring[len(k.ring)-1]...)
+	case join:
+		k.ring[len(k.ring)-1] = append(k.ring[len(k.ring)-1], text...)
+	default:
+		k.ring = append(k.ring, text)
+		if remove := len(k.ring) - killRingSize; 0 < remove {
+			k.ring = append(k.ring[:0], k.ring[remove:]...)
+		}
+	}
+	t.lastKill(false)
+}
+
+// KillLineEnd kill runes from cursor to end of line. At end of line
+// new line rune is killed. Key: Ctrl+K.
+func (t *TextField) KillLineEnd() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	t.selection.active = false
+	to := t.lineEnd(t.cursor)
+	if to == t.cursor && to < len(t.text) {
+		to++
+	}
+	t.killRange(t.cursor, to, false)
+}
+
+// KillLineStart kill runes from begin of line to cursor. Key: Ctrl+U.
+func (t *TextField) KillLineStart() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	t.selection.active = false
+	t.killRange(t.lineStart(t.cursor), t.cursor, true)
+}
+
+// KillWord kill runes from begin of whitespace delimited word to
+// cursor. Key: Ctrl+W.
+func (t *TextField) KillWord() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	t.selection.active = false
+	from := t.cursor
+	for 0 < from && unicode.IsSpace(t.text[from-1]) {
+		from--
+	}
+	for 0 < from && !unicode.IsSpace(t.text[from-1]) {
+		from--
+	}
+	t.killRange(from, t.cursor, true)
+}
+
+// Yank insert last killed text at cursor. Key: Ctrl+Y.
+func (t *TextField) Yank() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	k := &t.kill
+	if len(k.ring) == 0 {
+		return
+	}
+	from, to, ok := t.GetSelection()
+	if !ok {
+		from, to = t.cursor, t.cursor
+	}
+	t.selection.active = false
+	text := append([]rune{}, k.ring[len(k.ring)-1]...)
+	if !t.replace(from, to, text, from+len(text), false) {
+		return
+	}
+	k.from = from
+	k.index = len(k.ring) - 1
+	t.lastKill(true)
+}
+
+// YankPop replace text inserted by Yank or YankPop by previous killed
+// text. Key: Alt+Y.
+func (t *TextField) YankPop() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	k := &t.kill
+	if !t.continued() || !k.yank || len(k.ring) < 2 || t.cursor < k.from {
+		return
+	}
+	index := (k.index + len(k.ring) - 1) % len(k.ring)
+	text := append([]rune{}, k.ring[index]...)
+	if !t.replace(k.from, t.cursor, text, k.from+len(text), false) {
+		return
+	}
+	k.index = index
+	t.lastKill(true)
+}
+
+// TransposeChars swap grapheme cluster before cursor and at cursor and
+// move cursor forward. At end of line clusters before cursor are
+// swapped. Key: Ctrl+T.
+func (t *TextField) TransposeChars() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	t.selection.active = false
+	middle, end := t.cursor, clusterEnd(t.text, t.cursor)
+	if t.cursor == len(t.text) || t.text[t.cursor] == '\n' {
+		middle, end = t.clusterBefore(t.cursor), t.cursor
+	}
+	begin := t.clusterBefore(middle)
+	if middle == 0 || begin == middle || middle == end ||
+		t.text[begin] == '\n' || t.text[middle] == '\n' {
+		return
+	}
+	text := append(append([]rune{}, t.text[middle:end]...), t.text[begin:middle]...)
+	t.replace(begin, end, text, end, false)
+}
+
+// UpcaseWord convert word after cursor to upper case. Key: Alt+U.
+func (t *TextField) UpcaseWord() {
+	t.caseWord(func(first bool, r rune) rune {
+		return unicode.ToUpper(r)
+	})
+}
+
+// DowncaseWord convert word after cursor to lower case. Key: Alt+L.
+func (t *TextField) DowncaseWord() {
+	t.caseWord(func(first bool, r rune) rune {
+		return unicode.ToLower(r)
+	})
+}
+
+// CapitalizeWord convert first rune of word after cursor to title case
+// and other runes to lower case. Key: Alt+C.
+func (t *TextField) CapitalizeWord() {
+	t.caseWord(func(first bool, r rune) rune {
+		if first {
+			return unicode.ToTitle(r)
+		}
+		return unicode.ToLower(r)
+	})
+}
+
+// caseWord convert runes from cursor to end of word and move cursor to
+// end of word.
+func (t *TextField) caseWord(convert func(first bool, r rune) rune) {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	t.selection.active = false
+	from, to := t.cursor, t.clusterAlign(t.wordRight(t.cursor))
+	text := make([]rune, 0, to-from)
+	first, changed := true, false
+	for _, r := range t.text[from:to] {
+		c := r
+		if t.class(r) == wordRuneClass {
+			c = convert(first, r)
+			first = false
+		}
+		changed = changed || c != r
+		text = append(text, c)
+	}
+	if !changed {
+		t.cursor = to
+		return
+	}
+	t.replace(from, to, text, to, false)
+}
@@ -0,0 +1,180 @@
+package tf
+
+import "unicode"
+
+// Wrap is mode of breaking long lines
+type Wrap uint8
+
+const (
+	// WrapChar break line at any grapheme cluster
+	WrapChar Wrap = iota
+	// WrapWord break line after whitespaces and hyphens, around wide
+	// runes. Words longer of width are breaked as in WrapChar.
+	WrapWord
+	// WrapNone is not break lines, only '\n' starts new row
+	WrapNone
+)
+
+// SetWrap set mode of breaking long lines. By default WrapChar is used.
+func (t *TextField) SetWrap(wrap Wrap) {
+	if wrap == t.state.wrap {
+		return
+	}
+	t.state.wrap = wrap
+	t.state.changedContent = true
+}
+
+// SetTabWidth set distance between tab stops. Rune '\t' is expanded to
+// the next tab stop. If width is zero, then '\t' takes one column.
+func (t *TextField) SetTabWidth(width uint) {
+	if maxTab := uint(^uint8(0)); maxTab < width {
+		width = maxTab
+	}
+	if width == t.state.tabWidth {
+		return
+	}
+	t.state.tabWidth = width
+	t.state.changedContent = true
+}
+
+// SetTabFill set rune for drawing expanded '\t'. For example: ' ', '→',
+// '•'. Zero rune is blank.
+func (t *TextField) SetTabFill(fill rune) {
+	t.tabFill = fill
+}
+
+// isTab return true for expanded tab.
+func (t *TextField) isTab(r rune) bool {
+	return r == '\t' && t.state.tabWidth != 0
+}
+
+// layout calculate render position for each rune of text.
+// Length of render is length of text plus one for endtext.
+func (t *TextField) layout(text []rune, render []position, width uint, masked bool) {
+	opts := t.renderOptions()
+	// prepare render types
+	for i := 0; i < len(text); {
+		end := clusterEnd(text, i)
+		render[i].t = convert(text[i])
+		render[i].w = 1
+		if masked {
+			// each grapheme cluster is one mask rune
+			render[i].t = symbol
+			for k := i + 1; k < end; k++ {
+				render[k].t = extend
+				render[k].w = 0
+			}
+			i = end
+			continue
+		}
+		switch render[i].t {
+		case symbol:
+			render[i].w = clusterWidth(text[i:end])
+		case control:
+			render[i].w = uint8(len(opts.escape(text[i])))
+		}
+		for k := i + 1; k < end; k++ {
+			if text[k] == '\n' {
+				render[i].t = newline
+				render[i].w = 1
+			}
+			render[k].t = extend
+			render[k].w = 0
+		}
+		i = end
+	}
+	// render rows, cols calculations
+	var (
+		row, col  uint
+		base      int  // first rune of grapheme cluster
+		rowStart  int  // first rune of row
+		lastBreak = -1 // rune after break opportunity
+		breakNext bool // break opportunity after previous cluster
+		word      = t.state.wrap == WrapWord && !masked
+	)
+	place := func(i int) {
+		if render[i].t == extend {
+			render[i].row = render[base].row
+			render[i].col = render[base].col
+			return
+		}
+		base = i
+		if !masked && t.isTab(text[i]) {
+			// expand tab to the next tab stop
+			tab := t.state.tabWidth
+			w := tab - col%tab
+			if t.state.wrap != WrapNone && width < col+w {
+				w = width - col
+			}
+			render[i].w = uint8(w)
+		}
+		render[i].row = row
+		render[i].col = col
+		col += uint(render[i].w)
+	}
+	for i := range text {
+		if render[i].t == extend {
+			place(i)
+			continue
+		}
+		w := uint(render[i].w) // for tab is one column
+		if !masked && t.isTab(text[i]) {
+			w = 1
+		}
+		if breakNext || (word && w == 2) {
+			lastBreak = i
+		}
+		breakNext = false
+		if word && 0 < col && width < col+w && render[i].t != newline &&
+			unicode.IsSpace(text[i]) {
+			// blank at break opportunity hangs on the last column of row,
+			// cursor movement skips blanks in same cell
+			if width < col {
+				col = width
+			}
+			if t.isTab(text[i]) {
+				render[i].w = 1
+			}
+			base = i
+			render[i].row = row
+			render[i].col = col
+			col = width + 1
+			breakNext = true
+			continue
+		}
+		if t.state.wrap != WrapNone && 0 < col && width < col+w {
+			row++
+			col = 0
+			if word && rowStart < lastBreak {
+				// move runes after break opportunity to next row
+				for k := lastBreak; k < i; k++ {
+					place(k)
+				}
+				rowStart = lastBreak
+			} else {
+				rowStart = i
+			}
+		}
+		place(i)
+		if render[i].t == newline {
+			row++
+			col = 0
+			rowStart = i + 1
+			continue
+		}
+		breakNext = word && (w == 2 || unicode.IsSpace(text[i]) || isHyphen(text[i]))
+	}
+	if t.state.wrap != WrapNone && width < col+1 {
+		row++
+		col = 0
+	}
+	render[len(render)-1] = position{row: row, col: col, t: endtext, w: 1}
+}
+
+func isHyphen(r rune) bool {
+	switch r {
+	case '-', '‐', '–', '—':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,280 @@
+package tf
+
+import "unicode"
+
+// GoLexer is lexer of Go source code
+type GoLexer struct{}
+
+const (
+	goCode = iota
+	goComment
+	goRawString
+)
+
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true,
+	"continue": true, "default": true, "defer": true, "else": true,
+	"fallthrough": true, "for": true, "func": true, "go": true,
+	"goto": true, "if": true, "import": true, "interface": true,
+	"map": true, "package": true, "range": true, "return": true,
+	"select": true, "struct": true, "switch": true, "type": true,
+	"var": true,
+}
+
+var goBuiltins = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true,
+	"complex64": true, "complex128": true, "error": true,
+	"float32": true, "float64": true, "int": true, "int8": true,
+	"int16": true, "int32": true, "int64": true, "rune": true,
+	"string": true, "uint": true, "uint8": true, "uint16": true,
+	"uint32": true, "uint64": true, "uintptr": true,
+	"true": true, "false": true, "iota": true, "nil": true,
+	"append": true, "cap": true, "clear": true, "close": true,
+	"complex": true, "copy": true, "delete": true, "imag": true,
+	"len": true, "make": true, "max": true, "min": true, "new": true,
+	"panic": true, "print": true, "println": true, "real": true,
+	"recover": true,
+}
+
+// Lex implements Lexer.
+func (GoLexer) Lex(line []rune, state int) (tokens []Token, next int) {
+	l := lexer{line: line}
+	switch state {
+	case goComment:
+		if !l.until("*/") {
+			return l.add(TokenComment), goComment
+		}
+		l.add(TokenComment)
+	case goRawString:
+		if !l.until("`") {
+			return l.add(TokenString), goRawString
+		}
+		l.add(TokenString)
+	}
+	for l.pos < len(line) {
+		l.from = l.pos
+		r := line[l.pos]
+		switch {
+		case l.prefix("//"):
+			l.pos = len(line)
+			l.add(TokenComment)
+		case l.prefix("/*"):
+			l.pos += 2
+			if !l.until("*/") {
+				return l.add(TokenComment), goComment
+			}
+			l.add(TokenComment)
+		case r == '`':
+			l.pos++
+			if !l.until("`") {
+				return l.add(TokenString), goRawString
+			}
+			l.add(TokenString)
+		case r == '"' || r == '\'':
+			l.quoted()
+			l.add(TokenString)
+		case l.number():
+			l.add(TokenNumber)
+		case isIdentRune(r):
+			word := l.word()
+			switch {
+			case goKeywords[word]:
+				l.add(TokenKeyword)
+			case goBuiltins[word]:
+				l.add(TokenBuiltin)
+			}
+		case unicode.IsSpace(r):
+			l.pos++
+		default:
+			l.pos++
+			l.add(TokenOperator)
+		}
+	}
+	return l.tokens, goCode
+}
+
+// JSONLexer is lexer of JSON
+type JSONLexer struct{}
+
+// Lex implements Lexer.
+func (JSONLexer) Lex(line []rune, state int) (tokens []Token, next int) {
+	l := lexer{line: line}
+	for l.pos < len(line) {
+		l.from = l.pos
+		r := line[l.pos]
+		switch {
+		case r == '"':
+			l.quoted()
+			kind := TokenString
+			for p := l.pos; p < len(line); p++ {
+				if line[p] == ':' {
+					kind = TokenKey
+				}
+				if !unicode.IsSpace(line[p]) {
+					break
+				}
+			}
+			l.add(kind)
+		case l.number():
+			l.add(TokenNumber)
+		case isIdentRune(r):
+			switch l.word() {
+			case "true", "false", "null":
+				l.add(TokenKeyword)
+			}
+		case unicode.IsSpace(r):
+			l.pos++
+		default:
+			l.pos++
+			l.add(TokenOperator)
+		}
+	}
+	return l.tokens, 0
+}
+
+// INILexer is lexer of INI configuration files
+type INILexer struct{}
+
+// Lex implements Lexer.
+func (INILexer) Lex(line []rune, state int) (tokens []Token, next int) {
+	l := lexer{line: line}
+	for l.pos < len(line) && unicode.IsSpace(line[l.pos]) {
+		l.pos++
+	}
+	l.from = l.pos
+	if len(line) <= l.pos {
+		return nil, 0
+	}
+	switch line[l.pos] {
+	case ';', '#':
+		l.pos = len(line)
+		return l.add(TokenComment), 0
+	case '[':
+		l.until("]")
+		return l.add(TokenSection), 0
+	}
+	for l.pos < len(line) && line[l.pos] != '=' && line[l.pos] != ':' {
+		l.pos++
+	}
+	if len(line) <= l.pos {
+		return nil, 0
+	}
+	key := l.pos
+	for l.from < key && unicode.IsSpace(line[key-1]) {
+		key--
+	}
+	l.tokens = append(l.tokens, Token{From: l.from, To: key, Kind: TokenKey})
+	l.from = l.pos
+	l.pos++
+	l.add(TokenOperator)
+	for l.pos < len(line) && unicode.IsSpace(line[l.pos]) {
+		l.pos++
+	}
+	l.from = l.pos
+	if l.number() && len(line) <= l.pos {
+		return l.add(TokenNumber), 0
+	}
+	l.pos = len(line)
+	return l.add(TokenString), 0
+}
+
+// lexer is scanner of line
+type lexer struct {
+	line      []rune
+	from, pos int
+	tokens    []Token
+}
+
+// add token line[from:pos]
+func (l *lexer) add(kind TokenKind) []Token {
+	if l.from < l.pos {
+		l.tokens = append(l.tokens, Token{From: l.from, To: l.pos, Kind: kind})
+	}
+	l.from = l.pos
+	return l.tokens
+}
+
+// prefix return true if line at position starts with s
+func (l *lexer) prefix(s string) bool {
+	p := l.pos
+	for _, r := range s {
+		if len(l.line) <= p || l.line[p] != r {
+			return false
+		}
+		p++
+	}
+	return true
+}
+
+// until move position after s. If s is not found, then position is
+// moved to the end of line and result is false.
+func (l *lexer) until(s string) bool {
+	for ; l.pos < len(l.line); l.pos++ {
+		if l.prefix(s) {
+			l.pos += len([]rune(s))
+			return true
+		}
+	}
+	return false
+}
+
+// quoted move position after quoted string with escapes
+func (l *lexer) quoted() {
+	quote := l.line[l.pos]
+	for l.pos++; l.pos < len(l.line); l.pos++ {
+		switch l.line[l.pos] {
+		case '\\':
+			l.pos++
+		case quote:
+			l.pos++
+			return
+		}
+	}
+	l.pos = len(l.line)
+}
+
+// number move position after number
+func (l *lexer) number() bool {
+	p := l.pos
+	if p < len(l.line) && (l.line[p] == '-' || l.line[p] == '+') {
+		p++
+	}
+	if p < len(l.line) && l.line[p] == '.' {
+		p++
+	}
+	if len(l.line) <= p || !isDigit(l.line[p]) {
+		return false
+	}
+	if l.line[l.pos] == '+' || (l.line[l.pos] == '-' && 0 < len(l.tokens) &&
+		l.tokens[len(l.tokens)-1].Kind == TokenNumber) {
+		return false
+	}
+	for l.pos = p; l.pos < len(l.line); l.pos++ {
+		r := l.line[l.pos]
+		if (r == '-' || r == '+') && 0 < l.pos {
+			switch l.line[l.pos-1] {
+			case 'e', 'E', 'p', 'P':
+				continue
+			}
+		}
+		if !isIdentRune(r) && r != '.' {
+			break
+		}
+	}
+	return true
+}
+
+// word move position after identifier and return it
+func (l *lexer) word() string {
+	for ; l.pos < len(l.line) && isIdentRune(l.line[l.pos]); l.pos++ {
+	}
+	return string(l.line[l.from:l.pos])
+}
+
+func isDigit(r rune) bool {
+	return '0' <= r && r <= '9'
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,75 @@
+package tf
+
+import "time"
+
+// now is clock for revealing of masked runes
+var now = time.Now
+
+// SetMask set rune for drawing of each grapheme cluster instead of text,
+// for example '*' for passwords. Selected text cannot be copied from
+// masked field and changes of masked text are not stored in history.
+// Slices returned by GetText of masked field are overwritten by zero
+// after each change. For disable masking use zero rune.
+func (t *TextField) SetMask(mask rune) {
+	t.mask.r = mask
+	t.mask.until = time.Time{}
+	t.state.changedContent = true
+}
+
+// SetMaskReveal set duration of showing last typed rune in masked field.
+// By default typed rune is not showed.
+func (t *TextField) SetMaskReveal(d time.Duration) {
+	t.mask.reveal = d
+}
+
+// Clear remove text, history, kill ring and selection. Runes of text,
+// history and kill ring are overwritten by zero for reducing time of
+// secrets in memory.
+func (t *TextField) Clear() {
+	zeroRunes(t.text)
+	for _, k := range t.kill.ring {
+		zeroRunes(k)
+	}
+	t.forgetHistory()
+	t.ResetHistory()
+	t.kill = killRing{}
+	t.text = nil
+	t.spans = nil
+	t.cursor = 0
+	t.selection.active = false
+	t.mask.until = time.Time{}
+	t.state.changedContent = true
+	if t.state.init {
+		t.updateWidth()
+	}
+}
+
+// zeroRunes overwrite runes by zero
+func zeroRunes(rs []rune) {
+	for i := range rs {
+		rs[i] = 0
+	}
+}
+
+// masked return true if text is masked
+func (t *TextField) masked() bool {
+	return t.mask.r != 0
+}
+
+// revealTyped show typed rune at position for reveal duration
+func (t *TextField) revealTyped(pos int) {
+	if !t.masked() || t.mask.reveal <= 0 {
+		return
+	}
+	t.mask.pos = pos
+	t.mask.until = now().Add(t.mask.reveal)
+}
+
+// maskRune return rune for drawing of masked cluster at position
+func (t *TextField) maskRune(p int) rune {
+	if p == t.mask.pos && !t.mask.until.IsZero() && now().Before(t.mask.until) &&
+		convert(t.text[p]) == symbol && runeWidth(t.text[p]) == 1 {
+		return t.text[p]
+	}
+	return t.mask.r
+}
@@ -0,0 +1,249 @@
+package tf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Format is kind of value in NumericField
+type Format uint8
+
+const (
+	// FormatInteger is integer value, for example "-1 234"
+	FormatInteger Format = iota
+	// FormatFloat is floating-point value, for example "1 234.56"
+	FormatFloat
+)
+
+// ErrOutOfRange is error of value outside of bounds
+var ErrOutOfRange = errors.New("value out of range")
+
+// NumericField is field of number. Edits with not valid numbers are
+// rejected. Group separators are optional in typed text.
+type NumericField struct {
+	TextField
+
+	format    Format
+	min, max  float64
+	decimals  int  // amount of decimal places, negative for any
+	group     rune // thousands separator, zero if not used
+	separator rune // decimal separator
+	hasBounds bool
+}
+
+// NewNumericField return numeric field for format.
+func NewNumericField(format Format) *NumericField {
+	n := &NumericField{format: format, decimals: -1, separator: '.'}
+	n.Validator = ValidatorFunc(n.validate)
+	n.SetRejectInvalid(true)
+	return n
+}
+
+// SetBounds set minimal and maximal values.
+func (n *NumericField) SetBounds(min, max float64) {
+	n.min, n.max = min, max
+	n.hasBounds = true
+}
+
+// SetDecimals set amount of decimal places for FormatFloat. Typed text
+// cannot have more decimal places. For any amount use negative value.
+func (n *NumericField) SetDecimals(places int) {
+	n.decimals = places
+}
+
+// SetGrouping set thousands separator. For disable grouping use zero rune.
+func (n *NumericField) SetGrouping(separator rune) {
+	n.group = separator
+}
+
+// SetDecimalSeparator set decimal separator, for example ',' for
+// some locales. By default separator is '.'.
+func (n *NumericField) SetDecimalSeparator(separator rune) {
+	if separator == 0 {
+		separator = '.'
+	}
+	n.separator = separator
+}
+
+// canonical return text in strconv syntax
+func (n *NumericField) canonical(text []rune) string {
+	var sb strings.Builder
+	for _, r := range text {
+		switch {
+		case n.group != 0 && r == n.group:
+			// group separator is ignored
+		case r == n.separator:
+			sb.WriteRune('.')
+		case r == '.' && n.separator != '.':
+			// not valid for locale
+			sb.WriteRune('#')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// validate check text of field
+func (n *NumericField) validate(text []rune) error {
+	s := n.canonical(text)
+	var err error
+	if n.format == FormatInteger {
+		err = validInteger([]rune(s))
+	} else {
+		err = validFloat([]rune(s))
+		if n.decimals >= 0 && !strings.ContainsAny(s, "eEpPxX") {
+			if dot := strings.IndexRune(s, '.'); 0 <= dot && n.decimals < len(s)-dot-1 {
+				return fmt.Errorf("more than %d decimal places", n.decimals)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !n.hasBounds {
+		return nil
+	}
+	var below, above bool
+	v, _ := strconv.ParseFloat(s, 64)
+	if n.format == FormatInteger {
+		i, _ := strconv.ParseInt(s, 10, 64)
+		low, high := n.intBounds()
+		below, above = i < low, high < i
+	} else {
+		if math.IsNaN(v) {
+			return fmt.Errorf("%w: %v", ErrOutOfRange, v)
+		}
+		below, above = v < n.min, n.max < v
+	}
+	if !below && !above {
+		return nil
+	}
+	if (0 <= v && below) || (v <= 0 && above) {
+		// more digits can move value into bounds
+		return fmt.Errorf("%w: %v", ErrIncomplete, ErrOutOfRange)
+	}
+	return fmt.Errorf("%w: %v not in [%v, %v]", ErrOutOfRange, v, n.min, n.max)
+}
+
+// GetInt64 return value of text. Float value is rounded.
+func (n *NumericField) GetInt64() (int64, error) {
+	s := n.canonical(n.text)
+	if n.format == FormatInteger {
+		return strconv.ParseInt(s, 10, 64)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	v = math.Round(v)
+	if v < math.MinInt64 || math.MaxInt64 <= v || math.IsNaN(v) {
+		return 0, fmt.Errorf("%w: %v", ErrOutOfRange, v)
+	}
+	return int64(v), nil
+}
+
+// GetFloat64 return value of text.
+func (n *NumericField) GetFloat64() (float64, error) {
+	return strconv.ParseFloat(n.canonical(n.text), 64)
+}
+
+// intBounds return bounds of FormatInteger value.
+func (n *NumericField) intBounds() (low, high int64) {
+	low, high = math.MinInt64, math.MaxInt64
+	if !n.hasBounds {
+		return
+	}
+	// float64(math.MaxInt64) is 2^63
+	if math.MinInt64 < n.min {
+		low = int64(math.Ceil(n.min))
+	}
+	if n.max < math.MaxInt64 {
+		high = int64(math.Floor(n.max))
+	}
+	return
+}
+
+// SetInt64 set text of value limited by bounds.
+func (n *NumericField) SetInt64(v int64) {
+	if n.format != FormatInteger {
+		n.SetFloat64(float64(v))
+		return
+	}
+	low, high := n.intBounds()
+	if v < low {
+		v = low
+	}
+	if high < v {
+		v = high
+	}
+	n.SetText(n.display(strconv.FormatInt(v, 10)))
+}
+
+// SetFloat64 set text of value limited by bounds. Integer value is
+// rounded and float value is rounded to decimal places.
+func (n *NumericField) SetFloat64(v float64) {
+	if n.format == FormatInteger {
+		switch v = math.Round(v); {
+		case math.IsNaN(v):
+			return
+		case v <= math.MinInt64:
+			n.SetInt64(math.MinInt64)
+		case math.MaxInt64 <= v:
+			n.SetInt64(math.MaxInt64)
+		default:
+			n.SetInt64(int64(v))
+		}
+		return
+	}
+	if math.IsNaN(v) {
+		return
+	}
+	if n.hasBounds {
+		v = math.Max(n.min, math.Min(n.max, v))
+	}
+	n.SetText(n.display(strconv.FormatFloat(v, 'f', n.decimals, 64)))
+}
+
+// Normalize format text of valid value with grouping and decimal places.
+func (n *NumericField) Normalize() {
+	if n.validate(n.text) != nil {
+		return
+	}
+	if n.format == FormatInteger {
+		if v, err := n.GetInt64(); err == nil {
+			n.SetInt64(v)
+		}
+		return
+	}
+	if v, err := n.GetFloat64(); err == nil {
+		n.SetFloat64(v)
+	}
+}
+
+// display return text of value in strconv syntax
+func (n *NumericField) display(s string) []rune {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	integer, fraction := s, ""
+	if dot := strings.IndexByte(s, '.'); 0 <= dot {
+		integer, fraction = s[:dot], s[dot+1:]
+	}
+	text := []rune(sign)
+	for i, r := range integer {
+		if n.group != 0 && 0 < i && (len(integer)-i)%3 == 0 {
+			text = append(text, n.group)
+		}
+		text = append(text, r)
+	}
+	if fraction != "" {
+		text = append(text, n.separator)
+		text = append(text, []rune(fraction)...)
+	}
+	return text
+}
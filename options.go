@@ -0,0 +1,121 @@
+package tf
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// Visible is set of whitespace classes drawn by glyphs
+type Visible uint8
+
+const (
+	// VisibleSpaces draw spaces by GlyphSet.Space
+	VisibleSpaces Visible = 1 << iota
+	// VisibleTabs draw tabs by GlyphSet.Tab
+	VisibleTabs
+	// VisibleNewlines draw '\n' by GlyphSet.Newline
+	VisibleNewlines
+	// VisibleTrailing draw spaces and tabs at the end of line only
+	VisibleTrailing
+)
+
+// Escape is mode of drawing control runes
+type Escape uint8
+
+const (
+	// EscapeGlyph draw control rune by GlyphSet.Special
+	EscapeGlyph Escape = iota
+	// EscapeCaret draw control rune in caret notation: ^A, ^?, ^[E
+	EscapeCaret
+	// EscapeUnicode draw control rune as Go escape: \u0085
+	EscapeUnicode
+)
+
+// GlyphSet is runes for drawing whitespaces and control runes.
+// Zero rune is replaced by default glyph.
+type GlyphSet struct {
+	Space   rune // default '·'
+	Tab     rune // default '→'
+	Newline rune // default '↵'
+	Special rune // unusual whitespaces and control runes, default '•'
+}
+
+var defaultGlyphs = GlyphSet{
+	Space:   '·',
+	Tab:     '→',
+	Newline: '↵',
+	Special: '•',
+}
+
+// RenderOptions is options of drawing whitespaces and control runes
+type RenderOptions struct {
+	Visible Visible
+	Glyphs  GlyphSet
+	Escape  Escape
+}
+
+// SetRenderOptions set options of drawing whitespaces and control runes.
+func (t *TextField) SetRenderOptions(o RenderOptions) {
+	if o.Glyphs.Space == 0 {
+		o.Glyphs.Space = defaultGlyphs.Space
+	}
+	if o.Glyphs.Tab == 0 {
+		o.Glyphs.Tab = defaultGlyphs.Tab
+	}
+	if o.Glyphs.Newline == 0 {
+		o.Glyphs.Newline = defaultGlyphs.Newline
+	}
+	if o.Glyphs.Special == 0 {
+		o.Glyphs.Special = defaultGlyphs.Special
+	}
+	t.options = &o
+	t.state.changedContent = true
+}
+
+func (t *TextField) renderOptions() RenderOptions {
+	if t.options == nil {
+		return RenderOptions{Glyphs: defaultGlyphs}
+	}
+	return *t.options
+}
+
+func isControlRune(r rune) bool {
+	return r != '\n' && r != '\t' && unicode.Is(unicode.Cc, r)
+}
+
+// escape return runes for drawing control rune.
+func (o RenderOptions) escape(r rune) []rune {
+	switch o.Escape {
+	case EscapeCaret:
+		switch {
+		case r < 0x20:
+			return []rune{'^', r + 0x40}
+		case r == 0x7f:
+			return []rune{'^', '?'}
+		default: // C1 is ESC with rune
+			return []rune{'^', '[', r - 0x40}
+		}
+	case EscapeUnicode:
+		return []rune(fmt.Sprintf("\\u%04x", r))
+	}
+	return []rune{o.Glyphs.Special}
+}
+
+// visible return true, if whitespace at position pos with class flag
+// must be drawn by glyph.
+func (t *TextField) visible(o RenderOptions, flag Visible, pos int) bool {
+	if o.Visible&flag != 0 {
+		return true
+	}
+	return o.Visible&VisibleTrailing != 0 && t.trailing(pos)
+}
+
+// trailing return true, if rune at position pos is trailing whitespace.
+func (t *TextField) trailing(pos int) bool {
+	for ; pos < len(t.text) && t.text[pos] != '\n'; pos++ {
+		if !unicode.IsSpace(t.text[pos]) {
+			return false
+		}
+	}
+	return true
+}
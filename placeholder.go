@@ -0,0 +1,79 @@
+package tf
+
+// SetPlaceholder set hint text. Placeholder is drawn with Dim attribute
+// only if text is empty and is not part of text.
+func (t *TextField) SetPlaceholder(placeholder []rune) {
+	t.placeholder.text = append([]rune{}, placeholder...)
+	t.state.changedContent = true
+}
+
+// GetPlaceholder return hint text.
+func (t TextField) GetPlaceholder() []rune {
+	return t.placeholder.text
+}
+
+// showPlaceholder return true if placeholder is visible
+func (t *TextField) showPlaceholder() bool {
+	return len(t.text) == 0 && 0 < len(t.placeholder.render)
+}
+
+// layoutPlaceholder prepare render of placeholder for width without
+// cursor column.
+func (t *TextField) layoutPlaceholder(width uint) {
+	if len(t.text) != 0 || len(t.placeholder.text) == 0 {
+		t.placeholder.render = t.placeholder.render[:0]
+		return
+	}
+	size := len(t.placeholder.text) + 1
+	if cap(t.placeholder.render) < size {
+		t.placeholder.render = make([]position, size)
+	}
+	t.placeholder.render = t.placeholder.render[:size]
+	t.layout(t.placeholder.text, t.placeholder.render, width, false)
+}
+
+// placeholderHeight return amount of rows of visible placeholder.
+func (t *TextField) placeholderHeight() uint {
+	if !t.showPlaceholder() {
+		return 0
+	}
+	return t.placeholder.render[len(t.placeholder.render)-1].row + 1
+}
+
+// renderPlaceholder draw placeholder cells
+func (t *TextField) renderPlaceholder(draw func(p position, c Cell)) {
+	if !t.showPlaceholder() {
+		return
+	}
+	opts := t.renderOptions()
+	style := Style{Attr: Dim}
+	text, render := t.placeholder.text, t.placeholder.render
+	for p := range text {
+		pos := render[p]
+		switch pos.t {
+		case symbol:
+			if pos.w == 0 {
+				continue
+			}
+			c := Cell{R: text[p], Style: style}
+			end := p + 1
+			for end < len(text) && render[end].t == extend {
+				end++
+			}
+			if p+1 < end {
+				c.Comb = text[p+1 : end]
+			}
+			draw(pos, c)
+		case space:
+			for k := uint8(0); k < pos.w; k++ {
+				draw(position{row: pos.row, col: pos.col + uint(k), w: 1},
+					Cell{R: ' ', Style: style})
+			}
+		case control:
+			for k, r := range opts.escape(text[p]) {
+				draw(position{row: pos.row, col: pos.col + uint(k), w: 1},
+					Cell{R: r, Style: style})
+			}
+		}
+	}
+}
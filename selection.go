@@ -0,0 +1,201 @@
+package tf
+
+// SelectAll select all text.
+func (t *TextField) SelectAll() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	t.selection.active = true
+	t.selection.anchor = 0
+	t.cursor = len(t.text)
+}
+
+// SelectNone remove selection without text modification.
+func (t *TextField) SelectNone() {
+	t.selection.active = false
+}
+
+// SelectRange select runes between anchor and cursor position.
+// Cursor is moved to cursor position.
+func (t *TextField) SelectRange(anchor, cursor int) {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	if anchor < 0 {
+		anchor = 0
+	}
+	if len(t.text) < anchor {
+		anchor = len(t.text)
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	t.selection.active = true
+	t.selection.anchor = anchor
+	t.cursor = cursor
+}
+
+// SelectWord select word under cursor.
+func (t *TextField) SelectWord() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	from, to := t.cursor, t.cursor
+	if c := t.class(t.runeAt(t.cursor)); c == wordRuneClass {
+		for 0 < from && t.class(t.text[from-1]) == c {
+			from--
+		}
+		for to < len(t.text) && t.class(t.text[to]) == c {
+			to++
+		}
+	}
+	t.selection.active = true
+	t.selection.anchor = from
+	t.cursor = to
+}
+
+// SelectLine select logical line under cursor with ending '\n'.
+func (t *TextField) SelectLine() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	from, to := t.lineStart(t.cursor), t.lineEnd(t.cursor)
+	if to < len(t.text) {
+		to++ // add '\n'
+	}
+	t.selection.active = true
+	t.selection.anchor = from
+	t.cursor = to
+}
+
+// GetSelection return range of selected runes in text.
+// Selected runes is text[from:to].
+// If nothing is selected, then ok is false.
+func (t *TextField) GetSelection() (from, to int, ok bool) {
+	if !t.selection.active {
+		return 0, 0, false
+	}
+	from, to = t.selection.anchor, t.cursor
+	if to < from {
+		from, to = to, from
+	}
+	if len(t.text) < to {
+		to = len(t.text)
+	}
+	if to < from {
+		from = to
+	}
+	return from, to, from < to
+}
+
+// GetSelectedText return copy of selected runes.
+func (t *TextField) GetSelectedText() []rune {
+	from, to, ok := t.GetSelection()
+	if !ok || t.masked() {
+		return nil
+	}
+	return append([]rune{}, t.text[from:to]...)
+}
+
+// SelectPosition extend selection to position.
+func (t *TextField) SelectPosition(row, col uint) {
+	t.extend(func() { t.CursorPosition(row, col) })
+}
+
+// SelectMoveUp extend selection with moving cursor up.
+func (t *TextField) SelectMoveUp() {
+	t.extend(t.CursorMoveUp)
+}
+
+// SelectMoveDown extend selection with moving cursor down.
+func (t *TextField) SelectMoveDown() {
+	t.extend(t.CursorMoveDown)
+}
+
+// SelectMoveLeft extend selection with moving cursor left.
+func (t *TextField) SelectMoveLeft() {
+	t.extend(t.CursorMoveLeft)
+}
+
+// SelectMoveRight extend selection with moving cursor right.
+func (t *TextField) SelectMoveRight() {
+	t.extend(t.CursorMoveRight)
+}
+
+// SelectMoveHome extend selection to begin of visual row.
+func (t *TextField) SelectMoveHome() {
+	t.extend(t.CursorMoveHome)
+}
+
+// SelectMoveEnd extend selection to end of visual row.
+func (t *TextField) SelectMoveEnd() {
+	t.extend(t.CursorMoveEnd)
+}
+
+// SelectMoveLineHome extend selection to begin of logical line.
+func (t *TextField) SelectMoveLineHome() {
+	t.extend(t.CursorMoveLineHome)
+}
+
+// SelectMoveLineEnd extend selection to end of logical line.
+func (t *TextField) SelectMoveLineEnd() {
+	t.extend(t.CursorMoveLineEnd)
+}
+
+// SelectPageUp extend selection on page up.
+func (t *TextField) SelectPageUp() {
+	t.extend(t.CursorPageUp)
+}
+
+// SelectPageDown extend selection on page down.
+func (t *TextField) SelectPageDown() {
+	t.extend(t.CursorPageDown)
+}
+
+// SelectPageUp extend selection on limit lines up.
+func (t *TextFieldLimit) SelectPageUp() {
+	t.extend(t.CursorPageUp)
+}
+
+// SelectPageDown extend selection on limit lines down.
+func (t *TextFieldLimit) SelectPageDown() {
+	t.extend(t.CursorPageDown)
+}
+
+// extend selection from anchor to cursor position after move.
+func (t *TextField) extend(move func()) {
+	anchor := t.cursor
+	if t.selection.active {
+		anchor = t.selection.anchor
+	}
+	move()
+	t.selection.active = true
+	t.selection.anchor = anchor
+}
+
+// deleteSelection remove selected runes from text and return true,
+// if selection is not empty.
+func (t *TextField) deleteSelection() bool {
+	from, to, ok := t.GetSelection()
+	t.selection.active = false
+	if !ok {
+		return false
+	}
+	t.replace(from, to, nil, from, false)
+	return true
+}
+
+// runeAt return rune of text at position or rune before end of text.
+func (t *TextField) runeAt(pos int) rune {
+	if pos < len(t.text) {
+		return t.text[pos]
+	}
+	if 0 < pos && pos == len(t.text) {
+		return t.text[pos-1]
+	}
+	return '\n'
+}
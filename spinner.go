@@ -0,0 +1,216 @@
+package tf
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SetStep set step of Increment and Decrement. By default step is 1.
+// If digit is true, then step is place value of digit under cursor,
+// for example 10 for cursor on "2" in "123".
+func (t *TextField) SetStep(step float64, digit bool) {
+	t.step.size = step
+	t.step.digit = digit
+}
+
+// Increment add step to number in text, for example in field with
+// Integer or Float filter. Text without number is not changed, and
+// text is not changed if new number is rejected by Filter.
+func (t *TextField) Increment() {
+	t.spin(1, t.number())
+}
+
+// Decrement subtract step from number in text.
+func (t *TextField) Decrement() {
+	t.spin(-1, t.number())
+}
+
+// Increment add step to value.
+func (n *NumericField) Increment() {
+	n.spin(1, n.number())
+}
+
+// Decrement subtract step from value.
+func (n *NumericField) Decrement() {
+	n.spin(-1, n.number())
+}
+
+// number is access to number in text for Increment and Decrement
+type number struct {
+	text      string // text in strconv syntax
+	separator rune   // decimal separator of field text
+	integer   bool   // value is integer
+	getInt    func() (int64, error)
+	getFloat  func() (float64, error)
+	setInt    func(v int64)
+	setFloat  func(v float64)
+}
+
+func (t *TextField) number() number {
+	s := string(t.text)
+	_, err := strconv.ParseInt(s, 10, 64)
+	return number{
+		text:      s,
+		separator: '.',
+		integer:   len(t.text) == 0 || err == nil,
+		getInt:    func() (int64, error) { return strconv.ParseInt(s, 10, 64) },
+		getFloat:  func() (float64, error) { return strconv.ParseFloat(s, 64) },
+		setInt: func(v int64) {
+			t.setNumber([]rune(strconv.FormatInt(v, 10)))
+		},
+		setFloat: func(v float64) {
+			t.setNumber([]rune(strconv.FormatFloat(v, 'f', -1, 64)))
+		},
+	}
+}
+
+// setNumber set text of number. Text is not changed, if any rune is
+// rejected by Filter.
+func (t *TextField) setNumber(text []rune) {
+	if len(t.filter(text)) != len(text) {
+		return
+	}
+	t.SetText(text)
+}
+
+func (n *NumericField) number() number {
+	return number{
+		text:      n.canonical(n.text),
+		separator: n.separator,
+		integer:   n.format == FormatInteger,
+		getInt:    n.GetInt64,
+		getFloat:  n.GetFloat64,
+		setInt:    n.SetInt64,
+		setFloat:  n.SetFloat64,
+	}
+}
+
+func (t *TextField) spin(sign int, num number) {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	step := t.step.size
+	if step == 0 {
+		step = 1
+	}
+	if t.step.digit {
+		if place, ok := t.digitPlace(num); ok {
+			step = math.Pow10(place)
+		}
+	}
+	// cursor keep distance to end of text
+	tail := len(t.text) - t.cursor
+	if num.integer {
+		if !t.spinInt(sign, step, num) {
+			return
+		}
+	} else if !t.spinFloat(sign, step, num) {
+		return
+	}
+	t.selection.active = false
+	t.cursor = len(t.text) - tail
+	if t.cursor < 0 {
+		t.cursor = 0
+	}
+}
+
+// spinInt add step to integer value without conversion to float64.
+func (t *TextField) spinInt(sign int, step float64, num number) bool {
+	var v int64
+	if len(t.text) != 0 {
+		var err error
+		if v, err = num.getInt(); err != nil {
+			return false
+		}
+	}
+	d := int64(1)
+	if s := math.Round(math.Abs(step)); 1 < s {
+		d = math.MaxInt64
+		if s < math.MaxInt64 {
+			d = int64(s)
+		}
+	}
+	switch {
+	case 0 < sign && math.MaxInt64-d < v:
+		v = math.MaxInt64
+	case sign < 0 && v < math.MinInt64+d:
+		v = math.MinInt64
+	case 0 < sign:
+		v += d
+	default:
+		v -= d
+	}
+	num.setInt(v)
+	return true
+}
+
+// spinFloat add step to value and round it to decimal places of step
+// and text.
+func (t *TextField) spinFloat(sign int, step float64, num number) bool {
+	var v float64
+	if len(t.text) != 0 {
+		var err error
+		if v, err = num.getFloat(); err != nil {
+			return false
+		}
+	}
+	places := decimalPlaces(step)
+	if dot := strings.IndexByte(num.text, '.'); 0 <= dot {
+		if p := len(num.text) - dot - 1; places < p {
+			places = p
+		}
+	}
+	v += float64(sign) * step
+	if places < 16 {
+		// remove error of floating-point arithmetic
+		scale := math.Pow10(places)
+		v = math.Round(v*scale) / scale
+	}
+	num.setFloat(v)
+	return true
+}
+
+// digitPlace return power of ten for digit under cursor or before cursor.
+func (t *TextField) digitPlace(num number) (place int, ok bool) {
+	if strings.ContainsAny(num.text, "eEpPxXnN") {
+		return 0, false
+	}
+	pos := t.cursor
+	if len(t.text) <= pos || !isDigit(t.text[pos]) {
+		pos--
+	}
+	if pos < 0 || len(t.text) <= pos || !isDigit(t.text[pos]) {
+		return 0, false
+	}
+	dot := len(t.text)
+	for i, r := range t.text {
+		if r == num.separator {
+			dot = i
+			break
+		}
+	}
+	for i := pos + 1; i < dot; i++ {
+		if isDigit(t.text[i]) {
+			place++
+		}
+	}
+	for i := dot + 1; i <= pos; i++ {
+		if isDigit(t.text[i]) {
+			place--
+		}
+	}
+	return place, true
+}
+
+// decimalPlaces return amount of decimal places of value
+func decimalPlaces(v float64) int {
+	for p := 0; p < 16; p++ {
+		scaled := v * math.Pow10(p)
+		if math.Abs(scaled-math.Round(scaled)) < 1e-9*math.Max(1, math.Abs(scaled)) {
+			return p
+		}
+	}
+	return 16
+}
@@ -0,0 +1,152 @@
+package tf
+
+// Attr is set of text attributes
+type Attr uint16
+
+const (
+	Bold Attr = 1 << iota
+	Dim
+	Italic
+	Underline
+	Reverse
+	Blink
+	Strikethrough
+)
+
+// Color of text. Zero value is default color of terminal.
+type Color uint32
+
+const (
+	// ColorDefault is default color of terminal
+	ColorDefault Color = 0
+
+	colorPalette Color = 1 << 24
+	colorRGB     Color = 1 << 25
+)
+
+// PaletteColor return color from terminal palette: 0-15 ANSI colors,
+// 16-255 extended colors.
+func PaletteColor(n uint8) Color {
+	return colorPalette | Color(n)
+}
+
+// RGBColor return true color.
+func RGBColor(r, g, b uint8) Color {
+	return colorRGB | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// Palette return index of palette color.
+func (c Color) Palette() (n uint8, ok bool) {
+	return uint8(c), c&colorPalette != 0
+}
+
+// RGB return components of true color.
+func (c Color) RGB() (r, g, b uint8, ok bool) {
+	return uint8(c >> 16), uint8(c >> 8), uint8(c), c&colorRGB != 0
+}
+
+// Style of cell
+type Style struct {
+	Attr   Attr
+	Fg, Bg Color
+}
+
+// merge return style with attributes of both styles and colors of
+// style `s` if not default.
+func (base Style) merge(s Style) Style {
+	base.Attr |= s.Attr
+	if s.Fg != ColorDefault {
+		base.Fg = s.Fg
+	}
+	if s.Bg != ColorDefault {
+		base.Bg = s.Bg
+	}
+	return base
+}
+
+// Span is style of runes text[From:To]. Span is anchored to text and
+// moved by insertions and deletions before it.
+type Span struct {
+	From, To int
+	Style    Style
+}
+
+// AddSpan add style span. Styles of overlapped spans are merged in order
+// of adding.
+func (t *TextField) AddSpan(s Span) {
+	if s.To <= s.From {
+		return
+	}
+	t.spans = append(t.spans, s)
+}
+
+// SetSpans replace all style spans.
+func (t *TextField) SetSpans(spans []Span) {
+	t.spans = t.spans[:0]
+	for _, s := range spans {
+		t.AddSpan(s)
+	}
+}
+
+// GetSpans return copy of style spans.
+func (t *TextField) GetSpans() []Span {
+	return append([]Span{}, t.spans...)
+}
+
+// ClearSpans remove all style spans.
+func (t *TextField) ClearSpans() {
+	t.spans = nil
+}
+
+// moveSpans move spans after replacing runes text[from:to] by `size`
+// runes. Empty spans are removed.
+func moveSpans(spans []Span, from, to, size int) []Span {
+	delta := size - (to - from)
+	begin := func(p int) int {
+		switch {
+		case p < from || (p == from && from < to):
+			return p
+		case to <= p:
+			return p + delta
+		}
+		return from + size
+	}
+	end := func(p int) int {
+		switch {
+		case p <= from:
+			return p
+		case to <= p:
+			return p + delta
+		}
+		return from
+	}
+	result := spans[:0]
+	for _, s := range spans {
+		s.From, s.To = begin(s.From), end(s.To)
+		if s.From < s.To {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// styles return style for each rune of text or nil if no spans. Spans
+// of highlighter are overlapped by spans of TextField.
+func (t *TextField) styles() []Style {
+	spans := t.spans
+	if t.highlighter != nil {
+		spans = append(t.highlighter.spans(t.text), spans...)
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+	styles := make([]Style, len(t.text)+1)
+	for _, s := range spans {
+		for p := s.From; p < s.To && p < len(t.text); p++ {
+			if 0 <= p {
+				styles[p] = styles[p].merge(s.Style)
+			}
+		}
+	}
+	return styles
+}
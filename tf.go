@@ -2,11 +2,10 @@ package tf
 
 import (
 	"fmt"
+	"time"
 	"unicode"
 )
 
-type Format uint8
-
 func UnsignedInteger(r rune) (insert bool) {
 	switch r {
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
@@ -48,11 +47,14 @@ const (
 	space                  // 1
 	newline                // 2
 	endtext                // 3
+	extend                 // 4 - not first rune of grapheme cluster
+	control                // 5
 )
 
 type position struct {
 	row, col uint
 	t        symType
+	w        uint8 // amount of columns
 }
 
 type TextField struct {
@@ -62,31 +64,80 @@ type TextField struct {
 	text   []rune
 	Filter func(r rune) (insert bool)
 
+	// Validator check full text after each change. See SetRejectInvalid.
+	Validator     Validator
+	rejectInvalid bool
+
+	constraints Constraints // limits of text
+
+	clipboard Clipboard // nil for common in-memory clipboard
+
+	kill killRing // killed text for yanking
+
+	// WordRune is definition of word runes for word moving and deleting.
+	// If WordRune is nil, then DefaultWordRune is used.
+	WordRune func(r rune) (word bool)
+
+	pageSize uint // amount of rows for page moving
+
+	tabFill rune // rune for drawing expanded tab
+
+	options *RenderOptions // nil for default options
+
+	spans       []Span // style spans
+	highlighter *Highlighter
+
+	mask struct {
+		r      rune          // mask rune, zero if text is not masked
+		reveal time.Duration // duration of showing typed rune
+		pos    int           // position of typed rune
+		until  time.Time     // end of showing typed rune
+	}
+
+	inputMask []slot // template of text, nil if not used
+
+	placeholder struct {
+		text   []rune     // hint text for empty field
+		render []position // render of placeholder
+	}
+
+	step struct {
+		size  float64 // step of Increment and Decrement
+		digit bool    // step by digit under cursor
+	}
+
+	overflow struct {
+		left, right rune // indicators of hidden text in WrapNone mode
+	}
+
+	selection struct {
+		active bool
+		anchor int // position of selection begin in text
+	}
+
+	history history // undo and redo
+
 	state struct {
 		init           bool
 		changedContent bool
 		width          uint
+		wrap           Wrap
+		offset         uint // first visible column in WrapNone mode
+		tabWidth       uint // distance between tab stops
+		edits          uint // amount of text changes
 	}
 }
 
 func (t *TextField) SetText(text []rune) {
-	if len(text) == len(t.text) {
-		same := true
-		for i := range text {
-			if text[i] != t.text[i] {
-				same = false
-				break
-			}
-		}
-		if same {
-			return
-		}
+	if equalRunes(text, t.text) {
+		return
 	}
-	// Is need update?
-	defer func() {
-		t.state.changedContent = true
-	}()
-	t.text = text
+	t.selection.active = false
+	cursor := t.cursor
+	if len(text) < cursor {
+		cursor = len(text)
+	}
+	t.replace(0, len(t.text), text, cursor, false)
 }
 
 func (t TextField) GetText() []rune {
@@ -100,6 +151,14 @@ func (t *TextField) cursorInRect() {
 	if 0 < len(t.render) && len(t.render) <= int(t.cursor) {
 		t.cursor = (len(t.render)) - 1
 	}
+	if 0 < t.cursor && t.render[t.cursor].t == extend {
+		// cursor is always on begin of grapheme cluster
+		t.cursor = t.clusterStart(t.cursor)
+	}
+	for t.literalAt(t.cursor) {
+		// cursor is never on literal of input mask
+		t.cursor++
+	}
 }
 
 func (t *TextField) CursorPosition(row, col uint) {
@@ -107,36 +166,30 @@ func (t *TextField) CursorPosition(row, col uint) {
 	t.cursorInRect()
 	defer t.cursorInRect()
 	// action
+	t.selection.active = false
 	// find cursor position
-	if row == 0 && col == 0 {
-		t.cursor = 0
-		return
-	}
-	if last := len(t.render) - 1; t.render[last].row <= row &&
-		t.render[last].col <= col {
-		t.cursor = last
-		return
-	}
-	for i := range t.render {
-		if t.render[i].row == row && t.render[i].col == col {
-			t.cursor = i
-			return
-		}
-		if t.render[i].row == row+1 {
-			t.cursor = i - 1
-			return
-		}
-	}
-	for i := len(t.render) - 1; 0 <= i; i-- {
-		if t.render[i].col == col {
-			t.cursor = i
-			return
-		}
+	t.cursorOnRow(row, col)
+}
+
+// GetCursor return position of cursor in text.
+func (t *TextField) GetCursor() int {
+	t.cursorInRect()
+	return t.cursor
+}
+
+// SetCursor move cursor to position in text and remove selection.
+// Cursor is moved to begin of grapheme cluster and after literals of
+// input mask.
+func (t *TextField) SetCursor(pos int) {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	t.selection.active = false
+	if pos < 0 {
+		pos = 0
 	}
-	panic(fmt.Errorf(
-		"cursor is not found: %v %v %v",
-		row, col, t.render[len(t.render)-1],
-	))
+	t.cursor = pos
 }
 
 func (t *TextField) CursorMoveUp() {
@@ -144,6 +197,7 @@ func (t *TextField) CursorMoveUp() {
 	t.cursorInRect()
 	defer t.cursorInRect()
 	// action
+	t.selection.active = false
 	if t.cursor == 0 {
 		return
 	}
@@ -161,6 +215,7 @@ func (t *TextField) CursorMoveDown() {
 	t.cursorInRect()
 	defer t.cursorInRect()
 	// action
+	t.selection.active = false
 	if t.cursor == len(t.render)-1 {
 		return
 	}
@@ -172,10 +227,14 @@ func (t *TextField) CursorMoveLeft() {
 	t.cursorInRect()
 	defer t.cursorInRect()
 	// action
+	t.selection.active = false
 	if t.cursor == 0 {
 		return
 	}
 	t.cursor--
+	for 0 < t.cursor && (t.literalAt(t.cursor) || t.hanging(t.cursor)) {
+		t.cursor--
+	}
 }
 
 func (t *TextField) CursorMoveRight() {
@@ -183,30 +242,169 @@ func (t *TextField) CursorMoveRight() {
 	t.cursorInRect()
 	defer t.cursorInRect()
 	// action
+	t.selection.active = false
 	if t.cursor == len(t.render)-1 {
 		return
 	}
-	t.cursor++
-}
-
-// func (t *TextField) CursorMoveHome() {
-// 	fmt.Printf("HOLD")
-// }
-// func (t *TextField) CursorMoveEnd() {
-// 	fmt.Printf("HOLD")
-// }
-// func (t *TextField) CursorPageDown() {
-// 	fmt.Printf("HOLD")
-// }
-// func (t *TextField) CursorPageUp() {
-// 	fmt.Printf("HOLD")
-// }
-// func (t *TextField) SelectAll() { // DoubleClick
-// 	fmt.Printf("HOLD")
-// }
-
-// Insert rune, key Enter `\n` in text without update buffer.
-// After that function run `SetWidth` for update buffer.
+	t.cursor = clusterEnd(t.text, t.cursor)
+	for t.cursor < len(t.text) && t.hanging(t.cursor) {
+		t.cursor = clusterEnd(t.text, t.cursor)
+	}
+}
+
+// hanging return true, if blank at position is drawn in same cell as
+// previous blank, for example for hanging blanks at end of row in
+// WrapWord mode.
+func (t *TextField) hanging(pos int) bool {
+	if pos <= 0 || len(t.text) <= pos || !unicode.IsSpace(t.text[pos]) {
+		return false
+	}
+	prev := t.clusterBefore(pos)
+	return unicode.IsSpace(t.text[prev]) && t.render[prev].t != newline &&
+		t.render[prev].row == t.render[pos].row && t.render[prev].col == t.render[pos].col
+}
+
+// CursorMoveHome move cursor to begin of visual row.
+func (t *TextField) CursorMoveHome() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	t.selection.active = false
+	row := t.render[t.cursor].row
+	for 0 < t.cursor && t.render[t.cursor-1].row == row {
+		t.cursor--
+	}
+}
+
+// CursorMoveEnd move cursor to end of visual row.
+// For wrapped row cursor is placed before last rune of row.
+func (t *TextField) CursorMoveEnd() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	t.selection.active = false
+	row := t.render[t.cursor].row
+	for t.cursor < len(t.render)-1 && t.render[t.cursor+1].row == row {
+		t.cursor++
+	}
+}
+
+// CursorMoveLineHome move cursor to first non-blank rune of logical line
+// between '\n' runes. If cursor is already on it, then cursor is moved
+// to begin of logical line.
+func (t *TextField) CursorMoveLineHome() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	t.selection.active = false
+	start := t.lineStart(t.cursor)
+	first := start
+	for first < len(t.text) && t.text[first] != '\n' && unicode.IsSpace(t.text[first]) {
+		first++
+	}
+	if t.cursor == first {
+		t.cursor = start
+		return
+	}
+	t.cursor = first
+}
+
+// CursorMoveLineEnd move cursor to end of logical line between '\n' runes.
+func (t *TextField) CursorMoveLineEnd() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	t.selection.active = false
+	t.cursor = t.lineEnd(t.cursor)
+}
+
+// lineStart return index of first rune in logical line with rune `pos`.
+func (t *TextField) lineStart(pos int) int {
+	if len(t.text) < pos {
+		pos = len(t.text)
+	}
+	for 0 < pos && t.text[pos-1] != '\n' {
+		pos--
+	}
+	return pos
+}
+
+// lineEnd return index of '\n' or end of text for logical line
+// with rune `pos`.
+func (t *TextField) lineEnd(pos int) int {
+	for pos < len(t.text) && t.text[pos] != '\n' {
+		pos++
+	}
+	return pos
+}
+
+// SetPageSize set amount of rows for CursorPageUp and CursorPageDown.
+// By default page is height of render.
+func (t *TextField) SetPageSize(lines uint) {
+	t.pageSize = lines
+}
+
+// CursorPageDown move cursor down on page size rows with keeping column.
+func (t *TextField) CursorPageDown() {
+	t.cursorPage(t.page(), true)
+}
+
+// CursorPageUp move cursor up on page size rows with keeping column.
+func (t *TextField) CursorPageUp() {
+	t.cursorPage(t.page(), false)
+}
+
+func (t *TextField) page() uint {
+	if t.pageSize != 0 {
+		return t.pageSize
+	}
+	return t.GetRenderHeight()
+}
+
+func (t *TextField) cursorPage(lines uint, down bool) {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	t.selection.active = false
+	row, col := t.render[t.cursor].row, t.render[t.cursor].col
+	if down {
+		row += lines
+	} else if row < lines {
+		row = 0
+	} else {
+		row -= lines
+	}
+	t.cursorOnRow(row, col)
+}
+
+// cursorOnRow move cursor on row to the nearest column at left side or
+// on column. If row is outside of render, then last row is used.
+func (t *TextField) cursorOnRow(row, col uint) {
+	if last := t.render[len(t.render)-1].row; last < row {
+		row = last
+	}
+	pos := -1
+	for i := range t.render {
+		if t.render[i].row < row {
+			continue
+		}
+		if row < t.render[i].row {
+			break
+		}
+		if pos < 0 || t.render[i].col <= col {
+			pos = i
+		}
+	}
+	t.cursor = pos
+}
+
+// Insert rune, key Enter `\n` in text.
+// Selected text is replaced by rune.
 func (t *TextField) Insert(r rune) {
 	// cursor correction
 	t.cursorInRect()
@@ -215,47 +413,21 @@ func (t *TextField) Insert(r rune) {
 	if t.Filter != nil && !t.Filter(r) {
 		return
 	}
-	// Is need update?
-	defer func() {
-		t.cursor++
-		t.state.changedContent = true
-	}()
-	if t.cursor == 0 {
-		t.text = append([]rune{r}, t.text...)
-		t.render = append([]position{{row: 0, col: 0, t: symbol}}, t.render...)
-		return
+	from, to, ok := t.GetSelection()
+	if !ok {
+		from, to = t.cursor, t.cursor
+	}
+	t.selection.active = false
+	if t.replace(from, to, []rune{r}, from+1, true) {
+		t.revealTyped(from)
 	}
-	symT := convert(r)
-	// 	var addrow uint = 0
-	// 	if symT == newline {
-	// 		addrow = 1
-	// 	}
-	// 	if t.render[t.cursor].t == endtext {
-	// 		fmt.Println("=====", addrow)
-	// 		t.text = append(t.text, r)
-	// 		var row, col uint
-	// 		row = t.render[t.cursor].row
-	// 		col = t.render[t.cursor].col
-	//
-	// 		if symT == newline {
-	// 			row += 1
-	// 			col = 0
-	// 		}
-	//
-	// 		t.render[len(t.render)-1].t = symT
-	// 		t.render[len(t.render)-1].row = row
-	// 		t.render = append(t.render, position{row: row, col: col+1, t: endtext})
-	// 		return
-	// 	}
-	t.text = append(t.text[:t.cursor], append([]rune{r}, t.text[t.cursor:]...)...)
-	t.render = append(t.render[:t.cursor], append([]position{
-		position{row: 0, col: 0, t: symT},
-	}, t.render[t.cursor:]...)...)
 }
 
 func convert(r rune) symType {
 	if r == '\n' {
 		return newline
+	} else if isControlRune(r) {
+		return control
 	} else if unicode.IsSpace(r) && r != ' ' {
 		return space
 	}
@@ -270,15 +442,14 @@ func (t *TextField) KeyBackspace() {
 	if len(t.render) == 0 {
 		return
 	}
+	if t.deleteSelection() {
+		return
+	}
 	if t.cursor < 1 {
 		return
 	}
-	// Is need update?
-	defer func() {
-		t.state.changedContent = true
-	}()
-	t.text = append(t.text[:t.cursor-1], t.text[t.cursor:]...)
-	t.cursor--
+	from := t.clusterBefore(t.cursor)
+	t.replace(from, t.cursor, nil, from, false)
 }
 
 func (t *TextField) KeyDel() {
@@ -289,20 +460,41 @@ func (t *TextField) KeyDel() {
 	if len(t.render) == 0 {
 		return
 	}
-	// Is need update?
-	defer func() {
-		t.state.changedContent = true
-	}()
-	if len(t.render) == t.cursor+1 {
+	if t.deleteSelection() {
+		return
+	}
+	if len(t.text) <= t.cursor {
 		// nothing to do
 		return
 	}
-	t.text = append(t.text[:t.cursor], t.text[t.cursor+1:]...)
+	t.replace(t.cursor, clusterEnd(t.text, t.cursor), nil, t.cursor, false)
 }
 
+// Render draw text and cursor. Each grapheme cluster is reduced to its
+// first rune, so combining runes are not drawn. Use RenderCells for
+// drawing of full grapheme clusters.
 func (t *TextField) Render(
 	drawer func(row, col uint, r rune),
 	cursor func(row, col uint),
+) (height uint) {
+	return t.RenderCells(func(row, col uint, c Cell) {
+		drawer(row, col, c.R)
+	}, cursor)
+}
+
+// Cell is rendered part of text
+type Cell struct {
+	R        rune   // rune for drawing
+	Comb     []rune // combining runes of grapheme cluster, do not modify
+	Selected bool   // cell is part of selection
+	Style    Style  // style from spans
+}
+
+// RenderCells is same as Render, but drawer have additional information
+// about each cell.
+func (t *TextField) RenderCells(
+	drawer func(row, col uint, c Cell),
+	cursor func(row, col uint),
 ) (height uint) {
 	if !t.state.init || t.state.changedContent {
 		t.updateWidth()
@@ -319,30 +511,102 @@ func (t *TextField) Render(
 	t.cursorInRect()
 	defer t.cursorInRect()
 	// action
+	height = t.render[len(t.render)-1].row + 1
+	if h := t.placeholderHeight(); height < h {
+		height = h
+	}
+	view := newViewport(t, height)
+	draw := func(p position, c Cell) {
+		if !view.enable {
+			drawer(p.row, p.col, c)
+			return
+		}
+		if row, col, c, ok := view.cell(p, c); ok {
+			drawer(row, col, c)
+		}
+	}
+	t.renderPlaceholder(draw)
+	opts := t.renderOptions()
+	styles := t.styles()
+	from, to, _ := t.GetSelection()
 	for p := range t.render {
+		selected := from <= p && p < to
+		var style Style
+		if p < len(styles) {
+			style = styles[p]
+		}
 		switch t.render[p].t {
 		case symbol:
-			drawer(t.render[p].row, t.render[p].col, t.text[p])
+			if t.render[p].w == 0 {
+				// zero width cluster is not drawn
+				continue
+			}
+			c := Cell{R: t.text[p], Selected: selected, Style: style}
+			if t.masked() {
+				c.R = t.maskRune(p)
+				draw(t.render[p], c)
+				break
+			}
+			if c.R == ' ' && t.visible(opts, VisibleSpaces, p) {
+				c.R = opts.Glyphs.Space
+			}
+			if end := t.renderClusterEnd(p); p+1 < end {
+				c.Comb = t.text[p+1 : end]
+			}
+			draw(t.render[p], c)
 		case space:
-			drawer(t.render[p].row, t.render[p].col, '•')
+			tab := t.text[p] == '\t' && t.visible(opts, VisibleTabs, p)
+			if !t.isTab(t.text[p]) {
+				r := opts.Glyphs.Special
+				if tab {
+					r = opts.Glyphs.Tab
+				}
+				draw(t.render[p], Cell{R: r, Selected: selected, Style: style})
+				break
+			}
+			fill := t.tabFill
+			if fill == 0 {
+				fill = ' '
+			}
+			pos := t.render[p]
+			for k := uint8(0); k < t.render[p].w; k++ {
+				r := fill
+				if tab && k == 0 {
+					r = opts.Glyphs.Tab
+				}
+				draw(position{row: pos.row, col: pos.col + uint(k), w: 1},
+					Cell{R: r, Selected: selected, Style: style})
+			}
+		case control:
+			pos := t.render[p]
+			for k, r := range opts.escape(t.text[p]) {
+				draw(position{row: pos.row, col: pos.col + uint(k), w: 1},
+					Cell{R: r, Selected: selected, Style: style})
+			}
 		case newline:
-			// drawer(t.render[p].row, t.render[p].col, '↵')
+			if opts.Visible&VisibleNewlines != 0 {
+				draw(t.render[p], Cell{R: opts.Glyphs.Newline, Selected: selected, Style: style})
+			}
 		case endtext:
 			// drawer(t.render[p].row, t.render[p].col, 'X')
+		case extend:
+			// drawed with first rune of grapheme cluster
 		default:
 			panic(fmt.Errorf("undefined render symbol: %d", t.render[p].t))
 		}
 	}
+	view.indicators(drawer)
 	if cursor != nil {
-		cursor(t.render[t.cursor].row, t.render[t.cursor].col)
+		cursor(t.render[t.cursor].row, t.render[t.cursor].col-view.offset)
 	}
-
-	return t.render[len(t.render)-1].row + 1
+	return height
 }
 
-// runewidth is ignored.
+// Wide runes take 2 columns and never split on wrap, combining marks
+// take zero columns.
 //
-// runes '\t', '\v', '\f', '\r', U+0085 (NEL), U+00A0 (NBSP) are iterpreted as '\n'.
+// Control runes '\v', '\f', '\r', U+0085 (NEL) and others are drawn
+// in according to RenderOptions.
 //
 // function is panic free.
 func (t *TextField) SetWidth(width uint) {
@@ -377,6 +641,7 @@ func (t *TextField) updateWidth() {
 	const minWidth = 2
 	if width < minWidth {
 		t.render = []position{{row: 0, col: 0, t: endtext}} // reset render
+		t.placeholder.render = t.placeholder.render[:0]
 		return
 	}
 	// change width for cursor place
@@ -394,22 +659,8 @@ func (t *TextField) updateWidth() {
 		}
 	}
 
-	// prepare render types
-	for i := range text {
-		t.render[i].t = convert(text[i])
-	}
-	// render rows, cols calculations
-	var row, col uint
-	for i := range text {
-		t.render[i].row = row
-		t.render[i].col = col
-		col++
-		if t.render[i].t == newline || col == width {
-			row++
-			col = 0
-		}
-	}
-	t.render[len(t.render)-1] = position{row: row, col: col, t: endtext}
+	t.layout(text, t.render, width, t.masked())
+	t.layoutPlaceholder(width)
 }
 
 func (t *TextField) GetRenderHeight() (h uint) {
@@ -422,7 +673,11 @@ func (t *TextField) GetRenderHeight() (h uint) {
 	if last < 0 {
 		return 0
 	}
-	return t.render[last].row + 1
+	h = t.render[last].row + 1
+	if ph := t.placeholderHeight(); h < ph {
+		h = ph
+	}
+	return h
 }
 
 func (t *TextField) GetRenderWidth() uint {
@@ -445,9 +700,22 @@ func (t *TextFieldLimit) SetLinesLimit(lines uint) {
 	t.limitLines = lines
 }
 
+// Render is same as TextField.Render, but rows are limited.
+// Combining runes are not drawn, use RenderCells for drawing of them.
 func (t *TextFieldLimit) Render(
 	drawer func(row, col uint, r rune),
 	cursor func(row, col uint),
+) (height uint) {
+	return t.RenderCells(func(row, col uint, c Cell) {
+		drawer(row, col, c.R)
+	}, cursor)
+}
+
+// RenderCells is same as Render, but drawer have additional information
+// about each cell.
+func (t *TextFieldLimit) RenderCells(
+	drawer func(row, col uint, c Cell),
+	cursor func(row, col uint),
 ) (height uint) {
 	if !t.state.init || t.state.changedContent {
 		t.updateWidth()
@@ -461,19 +729,19 @@ func (t *TextFieldLimit) Render(
 		}
 	}()
 	if t.limitLines == 0 {
-		return t.TextField.Render(drawer, cursor)
+		return t.TextField.RenderCells(drawer, cursor)
 	}
 
 	offset := uint(0)
 	if t.limitLines < t.render[t.cursor].row+1 {
 		offset = t.render[t.cursor].row + 1 - t.limitLines
 	}
-	draw := func(row, col uint, r rune) {
+	draw := func(row, col uint, c Cell) {
 		if offset == 0 {
 			if t.limitLines <= row {
 				return
 			}
-			drawer(row, col, r)
+			drawer(row, col, c)
 			return
 		}
 		if row < offset {
@@ -482,7 +750,7 @@ func (t *TextFieldLimit) Render(
 		if offset+t.limitLines <= row {
 			return
 		}
-		drawer(row-offset, col, r)
+		drawer(row-offset, col, c)
 	}
 	var cur func(row, col uint)
 	if cursor != nil {
@@ -503,13 +771,31 @@ func (t *TextFieldLimit) Render(
 			cursor(row-offset, col)
 		}
 	}
-	height = t.TextField.Render(draw, cur)
+	height = t.TextField.RenderCells(draw, cur)
 	if t.limitLines < height {
 		height = t.limitLines
 	}
 	return
 }
 
+// CursorPageDown move cursor down on limit lines rows.
+func (t *TextFieldLimit) CursorPageDown() {
+	if t.limitLines == 0 {
+		t.TextField.CursorPageDown()
+		return
+	}
+	t.cursorPage(t.limitLines, true)
+}
+
+// CursorPageUp move cursor up on limit lines rows.
+func (t *TextFieldLimit) CursorPageUp() {
+	if t.limitLines == 0 {
+		t.TextField.CursorPageUp()
+		return
+	}
+	t.cursorPage(t.limitLines, false)
+}
+
 func (t *TextFieldLimit) GetRenderHeight() (h uint) {
 	defer func() {
 		if h == 0 {
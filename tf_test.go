@@ -2,10 +2,16 @@ package tf
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 var txts [][]rune
@@ -47,22 +53,25 @@ var widths = []uint{0, 1, 2, 3, 4, 10, 25, 50, 100}
 const testdata = "testdata"
 
 func Test(t *testing.T) {
-	for ti := range txts {
-		for wi := range widths {
-			name := fmt.Sprintf("%04d-%04d-%04d", len(txts[ti]), ti, widths[wi])
-			t.Run(name, func(t *testing.T) {
-				check(t, string(txts[ti]), wi, name)
-			})
+	for _, wrap := range []Wrap{WrapChar, WrapWord, WrapNone} {
+		for ti := range txts {
+			for wi := range widths {
+				name := fmt.Sprintf("%04d-%04d-%04d-%d", len(txts[ti]), ti, widths[wi], wrap)
+				t.Run(name, func(t *testing.T) {
+					check(t, string(txts[ti]), wi, wrap, name)
+				})
+			}
 		}
 	}
 }
 
-func check(t *testing.T, str string, wi int, name string) {
+func check(t *testing.T, str string, wi int, wrap Wrap, name string) {
 	// prepare variables
 	var (
 		buf bytes.Buffer
 		ta  = TextField{text: []rune(str)}
 	)
+	ta.SetWrap(wrap)
 	// compare
 	// defer func() {
 	// 	var (
@@ -205,10 +214,12 @@ func check(t *testing.T, str string, wi int, name string) {
 			ta.CursorPosition(1, 100)
 			ta.SetWidth(widths[wi])
 		}}, // 13
-		// {name: "CursorMoveHome", f: ta.CursorMoveHome},
-		// {name: "CursorMoveEnd", f: ta.CursorMoveEnd},
-		// {name: "CursorPageDown", f: ta.CursorPageDown},
-		// {name: "CursorPageUp", f: ta.CursorPageUp},
+		{name: "CursorMoveHome", f: ta.CursorMoveHome},         // 14
+		{name: "CursorMoveEnd", f: ta.CursorMoveEnd},           // 15
+		{name: "CursorMoveLineHome", f: ta.CursorMoveLineHome}, // 16
+		{name: "CursorMoveLineEnd", f: ta.CursorMoveLineEnd},   // 17
+		{name: "CursorPageDown", f: ta.CursorPageDown},         // 18
+		{name: "CursorPageUp", f: ta.CursorPageUp},             // 19
 	}
 	var ms []movement
 
@@ -498,6 +509,27 @@ func TestCursor(t *testing.T) {
 			},
 			eWidth: []int{6, 5, 4},
 		},
+		{
+			name: "HomeEnd",
+			text: "1234\n  12\n1234",
+			move: []func(fake){
+				func(ta fake) { ta.CursorPosition(1, 100) },
+				func(ta fake) { ta.CursorMoveLineHome() },
+				func(ta fake) { ta.CursorMoveLineHome() },
+				func(ta fake) { ta.CursorMoveLineEnd() },
+				func(ta fake) { ta.CursorMoveHome() },
+				func(ta fake) { ta.CursorMoveEnd() },
+			},
+			expect: []string{
+				"1234\n  12█\n1234\n",
+				"1234\n  █2\n1234\n",
+				"1234\n█ 12\n1234\n",
+				"1234\n  12█\n1234\n",
+				"1234\n█ 12\n1234\n",
+				"1234\n  12█\n1234\n",
+			},
+			eWidth: []int{4, 4, 4, 4, 4, 4},
+		},
 	}
 	for i := range tcs {
 		t.Run(tcs[i].name, func(t *testing.T) {
@@ -573,12 +605,1577 @@ func TestSet(t *testing.T) {
 	wg.Wait()
 }
 
+func TestHomeEndWrap(t *testing.T) {
+	ta := TextField{}
+	ta.SetText([]rune("123456789"))
+	ta.SetWidth(5)
+	ta.CursorPosition(1, 1)
+	tcs := []struct {
+		name   string
+		f      func()
+		expect int
+	}{
+		{name: "Home", f: ta.CursorMoveHome, expect: 4},
+		{name: "End", f: ta.CursorMoveEnd, expect: 7},
+		{name: "LineHome", f: ta.CursorMoveLineHome, expect: 0},
+		{name: "LineEnd", f: ta.CursorMoveLineEnd, expect: 9},
+		{name: "Home", f: ta.CursorMoveHome, expect: 8},
+	}
+	for i := range tcs {
+		tcs[i].f()
+		if ta.cursor != tcs[i].expect {
+			t.Errorf("%d %s: cursor %d != %d",
+				i, tcs[i].name, ta.cursor, tcs[i].expect)
+		}
+	}
+}
+
+func TestPage(t *testing.T) {
+	var text string
+	for i := 0; i < 30; i++ {
+		text += fmt.Sprintf("%02d:abc\n", i)
+	}
+	t.Run("TextField", func(t *testing.T) {
+		ta := TextField{}
+		ta.SetText([]rune(text))
+		ta.SetWidth(20)
+		ta.SetPageSize(4)
+		ta.CursorPosition(0, 3)
+		ta.CursorPageDown()
+		ta.CursorPageDown()
+		if p := ta.render[ta.cursor]; p.row != 8 || p.col != 3 {
+			t.Errorf("not valid page down: %v", p)
+		}
+		ta.CursorPageUp()
+		if p := ta.render[ta.cursor]; p.row != 4 || p.col != 3 {
+			t.Errorf("not valid page up: %v", p)
+		}
+		ta.SetPageSize(0)
+		ta.CursorPageDown()
+		if p := ta.render[ta.cursor]; p.row != 30 || p.col != 0 {
+			t.Errorf("not valid page down to end: %v", p)
+		}
+		ta.CursorPageUp()
+		if p := ta.render[ta.cursor]; p.row != 0 || p.col != 0 {
+			t.Errorf("not valid page up to begin: %v", p)
+		}
+	})
+	t.Run("TextFieldLimit", func(t *testing.T) {
+		ta := TextFieldLimit{}
+		ta.SetLinesLimit(10)
+		ta.SetText([]rune(text))
+		ta.SetWidth(20)
+		ta.CursorPosition(0, 5)
+		ta.CursorPageDown()
+		var b Buffer
+		ta.Render(b.Drawer, b.Cursor)
+		if p := ta.render[ta.cursor]; p.row != 10 || p.col != 5 {
+			t.Errorf("not valid page down: %v", p)
+		}
+		if actual := string(b[len(b)-1]); actual != "10:ab█" {
+			t.Errorf("not valid render: %s", actual)
+		}
+		ta.CursorPageUp()
+		if p := ta.render[ta.cursor]; p.row != 0 || p.col != 5 {
+			t.Errorf("not valid page up: %v", p)
+		}
+	})
+}
+
+func TestSelection(t *testing.T) {
+	render := func(ta *TextField) string {
+		var b Buffer
+		ta.RenderCells(func(row, col uint, c Cell) {
+			if c.Selected {
+				c.R = '_'
+			}
+			b.Drawer(row, col, c.R)
+		}, b.Cursor)
+		return b.Text()
+	}
+	tcs := []struct {
+		name   string
+		move   []func(ta *TextField)
+		expect string
+		sel    string
+	}{
+		{
+			name:   "All",
+			move:   []func(ta *TextField){(*TextField).SelectAll},
+			expect: "_______\n___█\n",
+			sel:    "foo bar\nbaz",
+		},
+		{
+			name: "Word",
+			move: []func(ta *TextField){
+				func(ta *TextField) { ta.CursorPosition(0, 5) },
+				(*TextField).SelectWord,
+			},
+			expect: "foo ___█\nbaz\n",
+			sel:    "bar",
+		},
+		{
+			name: "Line",
+			move: []func(ta *TextField){
+				func(ta *TextField) { ta.CursorPosition(0, 5) },
+				(*TextField).SelectLine,
+			},
+			expect: "_______\n█az\n",
+			sel:    "foo bar\n",
+		},
+		{
+			name: "Extend",
+			move: []func(ta *TextField){
+				func(ta *TextField) { ta.CursorPosition(0, 2) },
+				(*TextField).SelectMoveRight,
+				(*TextField).SelectMoveRight,
+				(*TextField).SelectMoveDown,
+				(*TextField).SelectMoveUp,
+			},
+			expect: "fo_█bar\nbaz\n",
+			sel:    "o",
+		},
+		{
+			name: "Reset",
+			move: []func(ta *TextField){
+				(*TextField).SelectAll,
+				(*TextField).CursorMoveLeft,
+			},
+			expect: "foo bar\nba█\n",
+			sel:    "",
+		},
+		{
+			name: "Insert",
+			move: []func(ta *TextField){
+				func(ta *TextField) { ta.CursorPosition(0, 4) },
+				(*TextField).SelectMoveLineEnd,
+				func(ta *TextField) { ta.Insert('W') },
+			},
+			expect: "foo W█\nbaz\n",
+			sel:    "",
+		},
+		{
+			name: "Backspace",
+			move: []func(ta *TextField){
+				func(ta *TextField) { ta.CursorPosition(1, 2) },
+				(*TextField).SelectMoveUp,
+				(*TextField).KeyBackspace,
+			},
+			expect: "fo█\n",
+			sel:    "",
+		},
+		{
+			name: "Del",
+			move: []func(ta *TextField){
+				func(ta *TextField) { ta.CursorPosition(1, 2) },
+				(*TextField).SelectMoveLineHome,
+				(*TextField).KeyDel,
+			},
+			expect: "foo bar\n█\n",
+			sel:    "",
+		},
+	}
+	for i := range tcs {
+		t.Run(tcs[i].name, func(t *testing.T) {
+			ta := TextField{}
+			ta.SetText([]rune("foo bar\nbaz"))
+			ta.SetWidth(20)
+			for _, m := range tcs[i].move {
+				m(&ta)
+			}
+			if actual := render(&ta); actual != tcs[i].expect {
+				t.Errorf("render is not same:\n%s\n%s", actual, tcs[i].expect)
+			}
+			if actual := string(ta.GetSelectedText()); actual != tcs[i].sel {
+				t.Errorf("selection is not same: `%s` != `%s`", actual, tcs[i].sel)
+			}
+		})
+	}
+}
+
+func TestUndo(t *testing.T) {
+	ta := TextField{}
+	ta.SetWidth(20)
+	check := func(text string, cursor int, modified bool) {
+		t.Helper()
+		if actual := string(ta.GetText()); actual != text {
+			t.Errorf("text is not same: `%s` != `%s`", actual, text)
+		}
+		if ta.cursor != cursor {
+			t.Errorf("cursor is not same: %d != %d", ta.cursor, cursor)
+		}
+		if ta.Modified() != modified {
+			t.Errorf("modified is not same: %v", ta.Modified())
+		}
+	}
+	for _, r := range "foo bar" {
+		ta.Insert(r)
+	}
+	check("foo bar", 7, true)
+	ta.MarkSaved()
+	check("foo bar", 7, false)
+	ta.CursorMoveLeft()
+	ta.KeyBackspace()
+	ta.KeyDel()
+	check("foo b", 5, true)
+	ta.Undo()
+	check("foo br", 5, true)
+	ta.Undo()
+	check("foo bar", 6, false)
+	ta.Undo()
+	check("foo", 3, true)
+	ta.Undo()
+	check("", 0, true)
+	if ta.Undo() {
+		t.Errorf("undo on empty history")
+	}
+	ta.Redo()
+	ta.Redo()
+	check("foo bar", 7, false)
+	ta.SetText([]rune("baz"))
+	check("baz", 3, true)
+	ta.Undo()
+	check("foo bar", 7, false)
+	ta.Insert('!')
+	if ta.Redo() {
+		t.Errorf("redo after modification")
+	}
+	check("foo bar!", 8, true)
+
+	ta.ResetHistory()
+	ta.SetHistoryLimit(2)
+	for _, r := range "a\nb\nc" {
+		ta.Insert(r)
+	}
+	for ta.Undo() {
+	}
+	check("foo bar!a\nb", 11, true)
+}
+
+func TestWord(t *testing.T) {
+	tcs := []struct {
+		text   string
+		word   func(r rune) bool
+		start  uint
+		move   func(ta *TextField)
+		stops  []int
+		result string
+	}{
+		{
+			text:  "Go (часто также Golang) — язык",
+			move:  (*TextField).CursorMoveWordRight,
+			stops: []int{2, 4, 9, 15, 22, 23, 25, 30, 30},
+		},
+		{
+			text:  "Go (часто также Golang) — язык",
+			start: 100,
+			move:  (*TextField).CursorMoveWordLeft,
+			stops: []int{26, 24, 22, 16, 10, 4, 3, 0, 0},
+		},
+		{
+			text:  "Hello, 世界\nfoo",
+			move:  (*TextField).CursorMoveWordRight,
+			stops: []int{5, 6, 8, 9, 10, 13},
+		},
+		{
+			text:  "fmt_print(a_b)",
+			move:  (*TextField).CursorMoveWordRight,
+			stops: []int{3, 4, 9, 10, 11, 12, 13, 14},
+		},
+		{
+			text:  "fmt_print(a_b)",
+			word:  IdentifierWordRune,
+			move:  (*TextField).CursorMoveWordRight,
+			stops: []int{9, 10, 13, 14},
+		},
+		{
+			text:   "fmt_print(a_b) + 1",
+			word:   IdentifierWordRune,
+			move:   func(ta *TextField) { ta.CursorPosition(0, 13); ta.KeyBackspaceWord() },
+			result: "fmt_print() + 1",
+		},
+		{
+			text:   "часто  также",
+			move:   func(ta *TextField) { ta.CursorPosition(0, 5); ta.KeyDelWord() },
+			result: "часто",
+		},
+	}
+	for i := range tcs {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			ta := TextField{WordRune: tcs[i].word}
+			ta.SetText([]rune(tcs[i].text))
+			ta.SetWidth(100)
+			ta.CursorPosition(0, tcs[i].start)
+			if tcs[i].result != "" {
+				tcs[i].move(&ta)
+				if actual := string(ta.GetText()); actual != tcs[i].result {
+					t.Errorf("result is not same: `%s` != `%s`", actual, tcs[i].result)
+				}
+				return
+			}
+			for _, stop := range tcs[i].stops {
+				tcs[i].move(&ta)
+				if ta.cursor != stop {
+					t.Errorf("cursor is not same: %d != %d", ta.cursor, stop)
+				}
+			}
+		})
+	}
+}
+
+func TestWide(t *testing.T) {
+	ta := TextField{}
+	ta.SetText([]rune("a世界b\nc"))
+	ta.SetWidth(5)
+	var b Buffer
+	ta.Render(b.Drawer, nil)
+	if actual, expect := b.Text(), "a世\n界b\nc\n"; actual != expect {
+		t.Errorf("render is not same:\n%s\n%s", actual, expect)
+	}
+	tcs := []struct {
+		move   func()
+		expect int
+	}{
+		{move: func() { ta.CursorPosition(0, 2) }, expect: 1},
+		{move: ta.CursorMoveDown, expect: 2},
+		{move: ta.CursorMoveRight, expect: 3},
+		{move: ta.CursorMoveUp, expect: 1},
+		{move: func() { ta.CursorPosition(1, 1) }, expect: 2},
+	}
+	for i := range tcs {
+		tcs[i].move()
+		if ta.cursor != tcs[i].expect {
+			t.Errorf("%d: cursor is not same: %d != %d", i, ta.cursor, tcs[i].expect)
+		}
+	}
+
+	ta.SetText([]rune("e\u0301x"))
+	b = Buffer{}
+	ta.Render(b.Drawer, nil)
+	if actual, expect := b.Text(), "ex\n"; actual != expect {
+		t.Errorf("render is not same:\n%s\n%s", actual, expect)
+	}
+	if w := ta.GetRenderWidth(); w != 2 {
+		t.Errorf("not valid width: %d", w)
+	}
+}
+
+func TestGrapheme(t *testing.T) {
+	t.Run("segmentation", func(t *testing.T) {
+		tcs := []struct {
+			text   string
+			expect []string
+		}{
+			{text: "e\u0301x", expect: []string{"e\u0301", "x"}},
+			{text: "👍🏽!", expect: []string{"👍🏽", "!"}},
+			{text: "👨\u200d👩\u200d👧", expect: []string{"👨\u200d👩\u200d👧"}},
+			{text: "🇷🇺🇯🇵🇩", expect: []string{"🇷🇺", "🇯🇵", "🇩"}},
+			{text: "a\r\nb", expect: []string{"a", "\r\n", "b"}},
+			{text: "\u1100\u1161\u11a8가", expect: []string{"\u1100\u1161\u11a8", "가"}},
+			{text: "☺\ufe0f", expect: []string{"☺\ufe0f"}},
+		}
+		for _, tc := range tcs {
+			text := []rune(tc.text)
+			var actual []string
+			for pos := 0; pos < len(text); {
+				end := clusterEnd(text, pos)
+				actual = append(actual, string(text[pos:end]))
+				pos = end
+			}
+			if fmt.Sprintf("%q", actual) != fmt.Sprintf("%q", tc.expect) {
+				t.Errorf("not same: %q != %q", actual, tc.expect)
+			}
+		}
+	})
+	t.Run("edit", func(t *testing.T) {
+		ta := TextField{}
+		ta.SetText([]rune("ae\u0301👍🏽🇷🇺b"))
+		ta.SetWidth(20)
+		for _, stop := range []int{1, 3, 5, 7, 8, 8} {
+			ta.CursorMoveRight()
+			if ta.cursor != stop {
+				t.Errorf("cursor is not same: %d != %d", ta.cursor, stop)
+			}
+		}
+		for _, stop := range []int{7, 5, 3, 1, 0} {
+			ta.CursorMoveLeft()
+			if ta.cursor != stop {
+				t.Errorf("cursor is not same: %d != %d", ta.cursor, stop)
+			}
+		}
+		var cells []string
+		ta.RenderCells(func(row, col uint, c Cell) {
+			cells = append(cells, fmt.Sprintf("%d:%s", col, string(append([]rune{c.R}, c.Comb...))))
+		}, nil)
+		if actual, expect := fmt.Sprintf("%q", cells),
+			fmt.Sprintf("%q", []string{"0:a", "1:e\u0301", "2:👍🏽", "4:🇷🇺", "6:b"}); actual != expect {
+			t.Errorf("cells is not same:\n%s\n%s", actual, expect)
+		}
+		var b Buffer
+		var comb TextField
+		comb.SetText([]rune("e\u0301x"))
+		comb.SetWidth(20)
+		comb.Render(b.Drawer, nil)
+		if actual, expect := b.Text(), "ex\n"; actual != expect {
+			t.Errorf("clusters are not reduced to first rune: %q != %q", actual, expect)
+		}
+		ta.CursorPosition(0, 3)
+		if ta.cursor != 3 {
+			t.Errorf("not valid cursor on wide cluster: %d", ta.cursor)
+		}
+		ta.KeyDel()
+		ta.CursorMoveRight()
+		ta.KeyBackspace()
+		if actual, expect := string(ta.GetText()), "ae\u0301b"; actual != expect {
+			t.Errorf("text is not same: %q != %q", actual, expect)
+		}
+		ta.KeyBackspace()
+		if actual, expect := string(ta.GetText()), "ab"; actual != expect {
+			t.Errorf("text is not same: %q != %q", actual, expect)
+		}
+	})
+}
+
+func TestWrap(t *testing.T) {
+	tcs := []struct {
+		text   string
+		width  uint
+		wrap   Wrap
+		expect string
+	}{
+		{
+			text:   "hello world foo",
+			width:  8,
+			wrap:   WrapChar,
+			expect: "hello w\norld fo\no\n",
+		},
+		{
+			text:   "hello world foo",
+			width:  8,
+			wrap:   WrapWord,
+			expect: "hello \nworld \nfoo\n",
+		},
+		{
+			text:   "abcdefghij-kl m",
+			width:  5,
+			wrap:   WrapWord,
+			expect: "abcd\nefgh\nij-\nkl m\n",
+		},
+		{
+			text:   "Hello, 世界!\nnext line",
+			width:  9,
+			wrap:   WrapWord,
+			expect: "Hello, \n世界!\nnext \nline\n",
+		},
+		{
+			text:   "hello world foo-bar end",
+			width:  6,
+			wrap:   WrapWord,
+			expect: "hello \nworld \nfoo-\nbar \nend\n",
+		},
+		{
+			text:   "hello world foo-bar end",
+			width:  8,
+			wrap:   WrapWord,
+			expect: "hello \nworld \nfoo-bar \nend\n",
+		},
+		{
+			text:   "ab   cd\tef",
+			width:  4,
+			wrap:   WrapWord,
+			expect: "ab  \ncd•\nef\n",
+		},
+		{
+			text:   "hello world foo\nbar",
+			width:  5,
+			wrap:   WrapNone,
+			expect: "hello\nbar\n",
+		},
+	}
+	for i := range tcs {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			ta := TextField{}
+			ta.SetText([]rune(tcs[i].text))
+			ta.SetWrap(tcs[i].wrap)
+			ta.SetWidth(tcs[i].width)
+			var b Buffer
+			ta.Render(b.Drawer, nil)
+			if actual := b.Text(); actual != tcs[i].expect {
+				t.Errorf("result is not same:\n%s\n%s", actual, tcs[i].expect)
+			}
+		})
+	}
+	t.Run("cursor", func(t *testing.T) {
+		ta := TextField{}
+		ta.SetText([]rune("hello world foo"))
+		ta.SetWrap(WrapWord)
+		ta.SetWidth(8)
+		ta.CursorPosition(1, 2)
+		if ta.cursor != 8 {
+			t.Errorf("not valid cursor position: %d", ta.cursor)
+		}
+		ta.CursorMoveDown()
+		if ta.cursor != 14 {
+			t.Errorf("not valid cursor down: %d", ta.cursor)
+		}
+		ta.CursorMoveHome()
+		if ta.cursor != 12 {
+			t.Errorf("not valid cursor home: %d", ta.cursor)
+		}
+	})
+	t.Run("hanging blanks", func(t *testing.T) {
+		ta := TextField{}
+		ta.SetText([]rune("abcd    efgh"))
+		ta.SetWrap(WrapWord)
+		ta.SetWidth(6)
+		ta.CursorPosition(0, 4)
+		for _, stop := range []int{5, 8, 9} {
+			ta.CursorMoveRight()
+			if ta.cursor != stop {
+				t.Errorf("not valid cursor right: %d != %d", ta.cursor, stop)
+			}
+		}
+		for _, stop := range []int{8, 5, 4} {
+			ta.CursorMoveLeft()
+			if ta.cursor != stop {
+				t.Errorf("not valid cursor left: %d != %d", ta.cursor, stop)
+			}
+		}
+	})
+}
+
+func TestViewport(t *testing.T) {
+	ta := TextField{}
+	ta.SetText([]rune("0123456789abcdef\n世界"))
+	ta.SetWrap(WrapNone)
+	ta.SetOverflow('<', '>')
+	ta.SetWidth(6)
+	tcs := []struct {
+		move   func()
+		expect string
+	}{
+		{
+			move:   func() { ta.CursorPosition(0, 100) },
+			expect: "<def█\n<\n",
+		},
+		{
+			move:   func() { ta.CursorPosition(0, 0) },
+			expect: "█1234>\n世界\n",
+		},
+		{
+			move: func() {
+				for i := 0; i < 6; i++ {
+					ta.CursorMoveRight()
+				}
+			},
+			expect: "<345█>\n<\n",
+		},
+		{
+			move:   func() { ta.SetOverflow(0, 0) },
+			expect: "2345█7\n界\n",
+		},
+	}
+	for i := range tcs {
+		tcs[i].move()
+		var b Buffer
+		ta.Render(b.Drawer, b.Cursor)
+		if actual := b.Text(); actual != tcs[i].expect {
+			t.Errorf("%d: result is not same:\n%s\n%s", i, actual, tcs[i].expect)
+		}
+	}
+}
+
+func TestTab(t *testing.T) {
+	tcs := []struct {
+		width  uint
+		tab    uint
+		fill   rune
+		wrap   Wrap
+		expect string
+	}{
+		{width: 20, tab: 0, expect: "a•b•c\n"},
+		{width: 20, tab: 4, expect: "a   b   c\n"},
+		{width: 20, tab: 4, fill: '→', expect: "a→→→b→→→c\n"},
+		{width: 6, tab: 4, fill: '→', expect: "a→→→b\n→→→→c\n"},
+		{width: 6, tab: 4, fill: '→', wrap: WrapWord, expect: "a→→→b→\nc\n"},
+		{width: 6, tab: 2, fill: '→', wrap: WrapNone, expect: "a→b→c\n"},
+	}
+	for i := range tcs {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			ta := TextField{}
+			ta.SetText([]rune("a\tb\tc"))
+			ta.SetTabWidth(tcs[i].tab)
+			ta.SetTabFill(tcs[i].fill)
+			ta.SetWrap(tcs[i].wrap)
+			ta.SetWidth(tcs[i].width)
+			var b Buffer
+			ta.Render(b.Drawer, nil)
+			if actual := b.Text(); actual != tcs[i].expect {
+				t.Errorf("result is not same:\n%s\n%s", actual, tcs[i].expect)
+			}
+		})
+	}
+	t.Run("cursor", func(t *testing.T) {
+		ta := TextField{}
+		ta.SetText([]rune("a\tb"))
+		ta.SetTabWidth(8)
+		ta.SetWidth(20)
+		ta.CursorPosition(0, 5)
+		if ta.cursor != 1 {
+			t.Errorf("cursor is not on tab: %d", ta.cursor)
+		}
+		ta.CursorMoveRight()
+		var b Buffer
+		ta.Render(b.Drawer, b.Cursor)
+		if actual, expect := b.Text(), "a       █\n"; actual != expect {
+			t.Errorf("result is not same:\n%s\n%s", actual, expect)
+		}
+	})
+}
+
+func TestRenderOptions(t *testing.T) {
+	tcs := []struct {
+		opts   *RenderOptions
+		expect string
+	}{
+		{
+			expect: "a b• \n••c  \n",
+		},
+		{
+			opts:   &RenderOptions{Visible: VisibleSpaces | VisibleTabs | VisibleNewlines},
+			expect: "a·b→·↵\n••c··\n",
+		},
+		{
+			opts:   &RenderOptions{Visible: VisibleTrailing},
+			expect: "a b→·\n••c··\n",
+		},
+		{
+			opts: &RenderOptions{
+				Visible: VisibleSpaces,
+				Glyphs:  GlyphSet{Space: '_', Special: '?'},
+			},
+			expect: "a_b?_\n??c__\n",
+		},
+		{
+			opts:   &RenderOptions{Escape: EscapeCaret},
+			expect: "a b• \n^A^[Ec  \n",
+		},
+		{
+			opts:   &RenderOptions{Escape: EscapeUnicode},
+			expect: "a b• \n\\u0001\\u0085c  \n",
+		},
+	}
+	for i := range tcs {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			ta := TextField{}
+			ta.SetText([]rune("a b\t \n\x01\u0085c  "))
+			if tcs[i].opts != nil {
+				ta.SetRenderOptions(*tcs[i].opts)
+			}
+			ta.SetWidth(20)
+			var b Buffer
+			ta.Render(b.Drawer, nil)
+			if actual := b.Text(); actual != tcs[i].expect {
+				t.Errorf("result is not same:\n%s\n%s", actual, tcs[i].expect)
+			}
+		})
+	}
+}
+
+func TestSpans(t *testing.T) {
+	bold := Style{Attr: Bold, Fg: PaletteColor(1)}
+	under := Style{Attr: Underline, Bg: RGBColor(1, 2, 3)}
+	var ta TextField
+	ta.SetText([]rune("hello world"))
+	ta.AddSpan(Span{From: 0, To: 5, Style: bold})
+	ta.AddSpan(Span{From: 4, To: 8, Style: under})
+
+	styled := func() string {
+		ta.SetWidth(20)
+		var s []rune
+		ta.RenderCells(func(row, col uint, c Cell) {
+			switch c.Style.Attr {
+			case Bold:
+				s = append(s, 'b')
+			case Underline:
+				s = append(s, 'u')
+			case Bold | Underline:
+				if c.Style.Fg != bold.Fg || c.Style.Bg != under.Bg {
+					t.Errorf("colors are not merged: %v", c.Style)
+				}
+				s = append(s, 'x')
+			default:
+				s = append(s, '.')
+			}
+		}, nil)
+		return string(s)
+	}
+	steps := []struct {
+		name   string
+		edit   func()
+		expect string
+	}{
+		{"initial", func() {}, "bbbbxuuu..."},
+		{"insert before", func() {
+			ta.CursorPosition(0, 0)
+			ta.Insert('>')
+		}, ".bbbbxuuu..."},
+		{"insert inside", func() {
+			ta.CursorPosition(0, 3)
+			ta.Insert('-')
+		}, ".bbbbbxuuu..."},
+		{"insert at end", func() {
+			ta.CursorPosition(0, 10)
+			ta.Insert('+')
+		}, ".bbbbbxuuu...."},
+		{"delete overlapped", func() {
+			ta.CursorPosition(0, 7)
+			ta.KeyBackspace()
+			ta.KeyBackspace()
+			ta.KeyBackspace()
+		}, ".bbbuuu...."},
+		{"delete span", func() {
+			ta.CursorPosition(0, 8)
+			for i := 0; i < 7; i++ {
+				ta.KeyBackspace()
+			}
+		}, "...."},
+	}
+	for _, st := range steps {
+		st.edit()
+		if actual := styled(); actual != st.expect {
+			t.Fatalf("%s: %s\n%s", st.name, actual, st.expect)
+		}
+	}
+	if n := len(ta.GetSpans()); n != 0 {
+		t.Errorf("empty spans are not removed: %d", n)
+	}
+	if n, ok := PaletteColor(200).Palette(); !ok || n != 200 {
+		t.Errorf("palette color: %d %v", n, ok)
+	}
+	if r, g, b, ok := RGBColor(1, 2, 3).RGB(); !ok || r != 1 || g != 2 || b != 3 {
+		t.Errorf("rgb color: %d %d %d %v", r, g, b, ok)
+	}
+}
+
+type countLexer struct {
+	Lexer
+	count int
+}
+
+func (c *countLexer) Lex(line []rune, state int) ([]Token, int) {
+	c.count++
+	return c.Lexer.Lex(line, state)
+}
+
+func TestHighlight(t *testing.T) {
+	kinds := func(l Lexer, text string) string {
+		h := Highlighter{Lexer: l}
+		var out []string
+		for i, tokens := range h.Tokens([]rune(text)) {
+			line := []rune(strings.Split(text, "\n")[i])
+			for _, tok := range tokens {
+				out = append(out, fmt.Sprintf("%d:%s", tok.Kind, string(line[tok.From:tok.To])))
+			}
+		}
+		return strings.Join(out, " ")
+	}
+	tcs := []struct {
+		lexer  Lexer
+		text   string
+		expect string
+	}{
+		{GoLexer{}, "func main() { // go", "1:func 6:( 6:) 6:{ 5:// go"},
+		{GoLexer{}, "x := len(`a\nb`) + 0x1F /* c\n */ 1.5e-3", "6:: 6:= 2:len 6:( 3:`a 3:b` 6:) 6:+ 4:0x1F 5:/* c 5: */ 4:1.5e-3"},
+		{GoLexer{}, `s := "a\"b" + 'c'`, `6:: 6:= 3:"a\"b" 6:+ 3:'c'`},
+		{JSONLexer{}, `{"a": [1, -2.5, true, "s"]}`, `6:{ 7:"a" 6:: 6:[ 4:1 6:, 4:-2.5 6:, 1:true 6:, 3:"s" 6:] 6:}`},
+		{INILexer{}, "; c\n[sec]\nkey = value\nn: 42", "5:; c 8:[sec] 7:key 6:= 3:value 7:n 6:: 4:42"},
+	}
+	for i := range tcs {
+		if actual := kinds(tcs[i].lexer, tcs[i].text); actual != tcs[i].expect {
+			t.Errorf("%d:\n%s\n%s", i, actual, tcs[i].expect)
+		}
+	}
+
+	// incremental lexing
+	lexer := &countLexer{Lexer: GoLexer{}}
+	var ta TextField
+	ta.SetHighlighter(&Highlighter{Lexer: lexer})
+	ta.SetText([]rune("package main\n\nfunc main() {\n\treturn\n}"))
+	ta.SetWidth(20)
+	render := func() []Style {
+		var styles []Style
+		ta.RenderCells(func(row, col uint, c Cell) {
+			styles = append(styles, c.Style)
+		}, nil)
+		return styles
+	}
+	styles := render()
+	if lexer.count != 5 {
+		t.Errorf("not valid amount of lexing: %d", lexer.count)
+	}
+	if styles[0] != DefaultTheme()[TokenKeyword] || styles[8] != (Style{}) {
+		t.Errorf("not valid styles: %v", styles[:9])
+	}
+	lexer.count = 0
+	ta.CursorPosition(1, 0)
+	ta.Insert('\n')
+	ta.Insert('/')
+	ta.Insert('*')
+	render()
+	if lexer.count != 4 {
+		t.Errorf("not valid amount of lexing after comment: %d", lexer.count)
+	}
+	ta.KeyBackspace()
+	render()
+	lexer.count = 0
+	ta.CursorPosition(0, 0)
+	ta.Insert('x')
+	render()
+	if lexer.count != 1 {
+		t.Errorf("not valid amount of lexing after edit: %d", lexer.count)
+	}
+}
+
+func TestPlaceholder(t *testing.T) {
+	var ta TextField
+	ta.SetPlaceholder([]rune("type your name"))
+	ta.SetWidth(9)
+	render := func() (string, uint, uint) {
+		var b Buffer
+		var row, col uint
+		ta.RenderCells(func(r, c uint, cell Cell) {
+			if cell.Style.Attr&Dim == 0 {
+				t.Errorf("placeholder is not dimmed: %c", cell.R)
+			}
+			b.Drawer(r, c, cell.R)
+		}, func(r, c uint) {
+			row, col = r, c
+		})
+		return b.Text(), row, col
+	}
+	text, row, col := render()
+	if text != "type you\nr name\n" || row != 0 || col != 0 {
+		t.Errorf("not valid placeholder: %q %d %d", text, row, col)
+	}
+	if h := ta.GetRenderHeight(); h != 2 {
+		t.Errorf("not valid height: %d", h)
+	}
+	if len(ta.GetText()) != 0 {
+		t.Errorf("placeholder is part of text")
+	}
+	ta.Insert('a')
+	var b Buffer
+	ta.Render(b.Drawer, nil)
+	if actual := b.Text(); actual != "a\n" || ta.GetRenderHeight() != 1 {
+		t.Errorf("placeholder is visible with text: %q", actual)
+	}
+	ta.KeyBackspace()
+	if text, _, _ := render(); text != "type you\nr name\n" {
+		t.Errorf("placeholder is not visible for empty text: %q", text)
+	}
+}
+
+func TestMask(t *testing.T) {
+	clock := time.Unix(0, 0)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	var ta TextField
+	ta.SetMask('*')
+	ta.SetMaskReveal(time.Second)
+	ta.SetWidth(20)
+	for _, r := range "páss 1" {
+		ta.Insert(r)
+	}
+	render := func() string {
+		var b Buffer
+		ta.Render(b.Drawer, nil)
+		return b.Text()
+	}
+	if actual := render(); actual != "*****1\n" {
+		t.Errorf("typed rune is not revealed: %q", actual)
+	}
+	clock = clock.Add(2 * time.Second)
+	if actual := render(); actual != "******\n" {
+		t.Errorf("not valid mask: %q", actual)
+	}
+	ta.Insert('x')
+	ta.CursorMoveLeft()
+	if actual := render(); actual != "******x\n" {
+		t.Errorf("typed rune is not revealed: %q", actual)
+	}
+	ta.KeyBackspace()
+	if actual := render(); actual != "******\n" {
+		t.Errorf("deleting is not hide revealed rune: %q", actual)
+	}
+	if actual := string(ta.GetText()); actual != "páss x" {
+		t.Errorf("not valid text: %q", actual)
+	}
+	if len(ta.history.changes) != 0 || ta.Undo() || !ta.Modified() {
+		t.Errorf("masked text is stored in history")
+	}
+	text := ta.GetText()
+	ta.Insert('!')
+	for _, r := range text {
+		if r != 0 {
+			t.Fatalf("old text is not zeroed: %q", string(text))
+		}
+	}
+	ta.SelectAll()
+	if sel := ta.GetSelectedText(); sel != nil {
+		t.Errorf("masked text is copied: %q", string(sel))
+	}
+	text = ta.GetText()
+	ta.Clear()
+	for _, r := range text {
+		if r != 0 {
+			t.Fatalf("text is not zeroed: %q", string(text))
+		}
+	}
+	if len(ta.GetText()) != 0 || ta.Undo() {
+		t.Errorf("text or history is not cleared")
+	}
+}
+
+func TestInputMask(t *testing.T) {
+	var ta TextField
+	if err := ta.SetInputMask("(999) 999-9999"); err != nil {
+		t.Fatal(err)
+	}
+	ta.SetWidth(20)
+	state := func() string {
+		ta.cursorInRect()
+		text := []rune(string(ta.GetText()))
+		return string(text[:ta.cursor]) + "|" + string(text[ta.cursor:])
+	}
+	steps := []struct {
+		action func()
+		expect string
+	}{
+		{func() { ta.Insert('5') }, "(5|"},
+		{func() { ta.Insert('x') }, "(5|"},
+		{func() { ta.Insert('5'); ta.Insert('5') }, "(555) |"},
+		{func() { ta.Insert(')') }, "(555) |"},
+		{func() { ta.KeyBackspace() }, "(55|"},
+		{func() { ta.Insert('5'); ta.Insert('1') }, "(555) 1|"},
+		{func() { ta.CursorMoveLeft() }, "(555) |1"},
+		{func() { ta.CursorMoveLeft() }, "(55|5) 1"},
+		{func() { ta.KeyDel() }, "(55|1) "},
+		{func() { ta.CursorMoveHome() }, "(|551) "},
+		{func() { ta.CursorMoveLeft() }, "(|551) "},
+		{func() { ta.SetText([]rune("5551234567")) }, "(5|55) 123-4567"},
+		{func() { ta.Insert('0') }, "(5|55) 123-4567"},
+		{func() { ta.CursorMoveEnd(); ta.KeyBackspace() }, "(555) 123-456|"},
+		{func() { ta.Undo() }, "(555) 123-4567|"},
+	}
+	for i, st := range steps {
+		st.action()
+		if actual := state(); actual != st.expect {
+			t.Fatalf("step %d:\n%s\n%s", i, actual, st.expect)
+		}
+	}
+	if raw := string(ta.GetRawText()); raw != "5551234567" {
+		t.Errorf("not valid raw text: %s", raw)
+	}
+	if err := ta.SetInputMask("9999-99-99"); err != nil {
+		t.Fatal(err)
+	}
+	if text := string(ta.GetText()); text != "5551-23-45" {
+		t.Errorf("text is not conformed: %s", text)
+	}
+	for _, template := range []string{"--", `99\`} {
+		if err := ta.SetInputMask(template); err == nil {
+			t.Errorf("error is not found for %q", template)
+		}
+	}
+	if err := ta.SetInputMask(`a\9`); err != nil {
+		t.Fatal(err)
+	}
+	ta.SetText([]rune("x"))
+	if text := string(ta.GetText()); text != "x9" {
+		t.Errorf("escaped literal is not valid: %s", text)
+	}
+	ta.MarkSaved()
+	if err := ta.SetInputMask(""); err != nil {
+		t.Fatal(err)
+	}
+	if text := string(ta.GetText()); text != "x9" || ta.Modified() {
+		t.Errorf("disabling of input mask change text: %s %v", text, ta.Modified())
+	}
+}
+
+func TestValidator(t *testing.T) {
+	tcs := []struct {
+		v     Validator
+		text  string
+		valid bool
+		inc   bool
+	}{
+		{UnsignedIntegerValidator, "", false, true},
+		{UnsignedIntegerValidator, "12", true, false},
+		{UnsignedIntegerValidator, "-1", false, false},
+		{IntegerValidator, "-", false, true},
+		{IntegerValidator, "-12", true, false},
+		{IntegerValidator, "+-", false, false},
+		{IntegerValidator, "99999999999999999999", false, false},
+		{FloatValidator, "1e", false, true},
+		{FloatValidator, "-.", false, true},
+		{FloatValidator, "0x1", false, true},
+		{FloatValidator, "-in", false, true},
+		{FloatValidator, "1.5e-3", true, false},
+		{FloatValidator, "Inf", true, false},
+		{FloatValidator, "+-..eE", false, false},
+		{FloatValidator, "1e400", false, false},
+		{FloatValidator, "1.2.", false, false},
+	}
+	for _, tc := range tcs {
+		err := tc.v.Validate([]rune(tc.text))
+		if valid, inc := err == nil, errors.Is(err, ErrIncomplete); valid != tc.valid || inc != tc.inc {
+			t.Errorf("%q: %v", tc.text, err)
+		}
+	}
+
+	var ta TextField
+	ta.Validator = IntegerValidator
+	ta.SetWidth(10)
+	for _, r := range "-1-" {
+		ta.Insert(r)
+	}
+	if text := string(ta.GetText()); text != "-1-" || ta.GetError() == nil {
+		t.Errorf("not valid marking: %q %v", text, ta.GetError())
+	}
+	ta.SetRejectInvalid(true)
+	ta.KeyBackspace()
+	ta.Insert('+')
+	ta.Insert('2')
+	if text := string(ta.GetText()); text != "-12" || ta.GetError() != nil {
+		t.Errorf("not valid rejecting: %q %v", text, ta.GetError())
+	}
+	ta.CursorMoveLineHome()
+	ta.KeyDel()
+	ta.KeyDel()
+	if text := string(ta.GetText()); text != "2" {
+		t.Errorf("not valid deleting: %q", text)
+	}
+	ta.SetText([]rune("x"))
+	if text := string(ta.GetText()); text != "2" {
+		t.Errorf("invalid text is set: %q", text)
+	}
+}
+
+func TestNumericField(t *testing.T) {
+	n := NewNumericField(FormatFloat)
+	n.SetGrouping(' ')
+	n.SetDecimalSeparator(',')
+	n.SetDecimals(2)
+	n.SetBounds(-1e6, 1e6)
+	n.SetWidth(20)
+	n.SetFloat64(1234567.891)
+	if text := string(n.GetText()); text != "1 000 000,00" {
+		t.Errorf("value is not limited: %q", text)
+	}
+	n.SetFloat64(-12345.678)
+	if text := string(n.GetText()); text != "-12 345,68" {
+		t.Errorf("not valid text: %q", text)
+	}
+	if v, err := n.GetFloat64(); err != nil || v != -12345.68 {
+		t.Errorf("not valid value: %v %v", v, err)
+	}
+	if v, err := n.GetInt64(); err != nil || v != -12346 {
+		t.Errorf("not valid integer value: %v %v", v, err)
+	}
+	n.CursorMoveLineEnd()
+	n.Insert('1')
+	n.KeyBackspace()
+	n.Insert('.')
+	if text := string(n.GetText()); text != "-12 345,6" {
+		t.Errorf("not valid editing: %q", text)
+	}
+	n.SetText([]rune("12345,5"))
+	n.Normalize()
+	if text := string(n.GetText()); text != "12 345,50" {
+		t.Errorf("not valid normalize: %q", text)
+	}
+
+	i := NewNumericField(FormatInteger)
+	i.SetBounds(10, 200)
+	i.SetWidth(20)
+	for _, r := range "1x50" {
+		i.Insert(r)
+	}
+	if text := string(i.GetText()); text != "150" || i.GetError() != nil {
+		t.Errorf("not valid integer: %q %v", text, i.GetError())
+	}
+	i.Insert('0')
+	if text := string(i.GetText()); text != "150" {
+		t.Errorf("value out of range: %q", text)
+	}
+	i.SetText([]rune("5"))
+	if err := i.GetError(); !errors.Is(err, ErrIncomplete) {
+		t.Errorf("not valid error: %v", err)
+	}
+	i.SetInt64(1000)
+	if v, err := i.GetInt64(); err != nil || v != 200 {
+		t.Errorf("not valid integer value: %v %v", v, err)
+	}
+
+	big := NewNumericField(FormatInteger)
+	big.SetBounds(0, 1<<62)
+	big.SetWidth(30)
+	big.SetInt64(1<<62 - 1)
+	if text := string(big.GetText()); text != "4611686018427387903" {
+		t.Errorf("not valid big integer: %s", text)
+	}
+	big.SetGrouping(',')
+	big.Normalize()
+	if text := string(big.GetText()); text != "4,611,686,018,427,387,903" {
+		t.Errorf("not valid normalize of big integer: %s", text)
+	}
+	big.SetInt64(-5)
+	if v, err := big.GetInt64(); err != nil || v != 0 {
+		t.Errorf("not valid lower bound: %v %v", v, err)
+	}
+
+	nan := NewNumericField(FormatFloat)
+	nan.SetBounds(0, 10)
+	nan.SetWidth(20)
+	nan.SetText([]rune("5"))
+	nan.SetText([]rune("NaN"))
+	nan.SetFloat64(math.NaN())
+	for _, r := range "NaN" {
+		nan.Insert(r)
+	}
+	if text := string(nan.GetText()); text != "5" || nan.GetError() != nil {
+		t.Errorf("NaN is accepted: %q %v", text, nan.GetError())
+	}
+}
+
+func TestSpin(t *testing.T) {
+	state := func(n *NumericField) string {
+		n.cursorInRect()
+		return string(n.text[:n.cursor]) + "|" + string(n.text[n.cursor:])
+	}
+	n := NewNumericField(FormatFloat)
+	n.SetGrouping(' ')
+	n.SetBounds(-10, 1500)
+	n.SetWidth(20)
+	n.SetStep(0.1, false)
+	n.SetText([]rune("0.2"))
+	n.Increment()
+	if actual := state(n); actual != "|0.3" {
+		t.Errorf("not valid increment: %s", actual)
+	}
+	n.SetStep(0, true)
+	n.SetText([]rune("999.5"))
+	n.CursorPosition(0, 0)
+	n.Increment()
+	if actual := state(n); actual != "1 |099.5" {
+		t.Errorf("not valid digit increment: %s", actual)
+	}
+	n.Increment()
+	if actual := state(n); actual != "1 |199.5" {
+		t.Errorf("not valid digit increment: %s", actual)
+	}
+	n.Increment()
+	n.Increment()
+	n.Increment()
+	n.Increment()
+	if actual := string(n.GetText()); actual != "1 500" {
+		t.Errorf("value is not limited: %s", actual)
+	}
+	n.SetText([]rune("1.25"))
+	n.CursorMoveLineEnd()
+	n.Decrement()
+	if actual := state(n); actual != "1.24|" {
+		t.Errorf("not valid decrement: %s", actual)
+	}
+	n.Undo()
+	if actual := string(n.GetText()); actual != "1.25" {
+		t.Errorf("not valid undo: %s", actual)
+	}
+
+	i := NewNumericField(FormatInteger)
+	i.SetWidth(20)
+	i.Decrement()
+	i.Decrement()
+	if actual := state(i); actual != "-2|" {
+		t.Errorf("not valid integer decrement: %s", actual)
+	}
+	i.SetText([]rune("9007199254740993"))
+	i.Increment()
+	if actual := string(i.GetText()); actual != "9007199254740994" {
+		t.Errorf("not valid large integer increment: %s", actual)
+	}
+
+	var f TextField
+	f.SetWidth(20)
+	f.Filter = Integer
+	f.SetText([]rune("19"))
+	f.CursorMoveLineEnd()
+	f.CursorMoveLeft()
+	f.Increment()
+	if actual := string(f.text[:f.cursor]) + "|" + string(f.text[f.cursor:]); actual != "2|0" {
+		t.Errorf("not valid text field increment: %s", actual)
+	}
+	f.Filter = Float
+	f.SetText([]rune("0.5"))
+	f.SetStep(0.25, false)
+	f.Decrement()
+	if actual := string(f.GetText()); actual != "0.25" {
+		t.Errorf("not valid text field decrement: %s", actual)
+	}
+	f.SetText([]rune("x"))
+	f.Increment()
+	if actual := string(f.GetText()); actual != "x" {
+		t.Errorf("not number is changed: %s", actual)
+	}
+	f.Filter = UnsignedInteger
+	f.SetStep(1, false)
+	f.SetText([]rune("0"))
+	f.Decrement()
+	if actual := string(f.GetText()); actual != "0" {
+		t.Errorf("filter is not used: %s", actual)
+	}
+}
+
+func TestFilters(t *testing.T) {
+	tcs := []struct {
+		name   string
+		filter func(r rune) bool
+		expect string
+	}{
+		{"Hex", Hex, "0189afAF"},
+		{"Octal", Octal, "01"},
+		{"Binary", Binary, "01"},
+		{"Identifier", Identifier, "0189afAFgz_я"},
+		{"ASCIIPrintable", ASCIIPrintable, "0189afAFgz_ -"},
+		{"NoWhitespace", NoWhitespace, "0189afAFgz_-я\x01"},
+		{"SingleLine", SingleLine, "0189afAFgz_ \t-я\x01"},
+		{"Regexp", FilterFromRegexp(regexp.MustCompile(`[a-f]`)), "af"},
+		{"AnyOf", AnyOf(Binary, FilterFromRegexp(regexp.MustCompile(`[a-f]`))), "01af"},
+		{"AllOf", AllOf(Hex, Not(UnsignedInteger)), "afAF"},
+		{"Not", Not(ASCIIPrintable), "\n\tя\x01"},
+	}
+	for _, tc := range tcs {
+		var accepted []rune
+		for _, r := range "0189afAFgz_ \n\t-я\x01" {
+			if tc.filter(r) {
+				accepted = append(accepted, r)
+			}
+		}
+		if actual := string(accepted); actual != tc.expect {
+			t.Errorf("%s: %q", tc.name, actual)
+		}
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	tcs := []struct {
+		c      Constraints
+		text   string
+		expect string
+	}{
+		{Constraints{MaxRunes: 3}, "abcd", ""},
+		{Constraints{MaxRunes: 3, Truncate: true}, "abcd", "abc"},
+		{Constraints{MaxGraphemes: 2, Truncate: true}, "e\u0301e\u0301e", "e\u0301e\u0301"},
+		{Constraints{MaxRunes: 2, Truncate: true}, "ae\u0301e", "a"},
+		{Constraints{MaxLines: 2, Truncate: true}, "a\nb\nc", "a\nb"},
+		{Constraints{MaxLineLength: 2, Truncate: true}, "ab\ncde", "ab\ncd"},
+		{Constraints{MaxLineLength: 2}, "ab\ncd", "ab\ncd"},
+	}
+	for i, tc := range tcs {
+		var ta TextField
+		ta.SetConstraints(tc.c)
+		ta.SetText([]rune(tc.text))
+		if actual := string(ta.GetText()); actual != tc.expect {
+			t.Errorf("%d: %q", i, actual)
+		}
+	}
+
+	var ta TextField
+	ta.SetConstraints(Constraints{MaxRunes: 5, MaxLines: 1, Truncate: true})
+	ta.SetWidth(10)
+	for _, r := range "ab\ncdefg" {
+		ta.Insert(r)
+	}
+	if actual := string(ta.GetText()); actual != "abcde" {
+		t.Errorf("typing is not limited: %q", actual)
+	}
+	ta.KeyBackspace()
+	ta.CursorMoveLineHome()
+	ta.Insert('x')
+	if actual := string(ta.GetText()); actual != "xabcd" {
+		t.Errorf("not valid text: %q", actual)
+	}
+
+	ta = TextField{}
+	ta.SetConstraints(Constraints{MaxRunes: 10, Truncate: true})
+	ta.SetText([]rune("abcdefgh"))
+	ta.SetWidth(20)
+	ta.SetCursor(8)
+	ta.ReplaceRange(1, 2, []rune("XYZW"))
+	if actual := string(ta.text[:ta.cursor]) + "|" + string(ta.text[ta.cursor:]); actual != "aXYZcdefgh|" {
+		t.Errorf("cursor is not moved by truncated runes: %q", actual)
+	}
+}
+
+func TestClipboard(t *testing.T) {
+	var a, b TextField
+	a.SetText([]rune("hello world"))
+	a.SetWidth(20)
+	b.SetWidth(20)
+	a.CursorPosition(0, 0)
+	a.SelectMoveWordRight()
+	if err := a.Cut(); err != nil {
+		t.Fatal(err)
+	}
+	b.Filter = NoWhitespace
+	b.SetConstraints(Constraints{MaxRunes: 7, Truncate: true})
+	b.SetText([]rune("12"))
+	b.CursorMoveLineEnd()
+	if err := b.Paste(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Paste(); err != nil {
+		t.Fatal(err)
+	}
+	if actual := string(a.GetText()) + "|" + string(b.GetText()); actual != " world|12hello" {
+		t.Errorf("not valid cut and paste: %s", actual)
+	}
+
+	var buf bytes.Buffer
+	a.SetClipboard(&OSC52Clipboard{Writer: &buf})
+	a.SelectAll()
+	if err := a.Copy(); err != nil {
+		t.Fatal(err)
+	}
+	if actual := buf.String(); actual != "\x1b]52;c;IHdvcmxk\a" {
+		t.Errorf("not valid OSC 52: %q", actual)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	err = (&OSC52Clipboard{}).Set([]rune("ok"))
+	os.Stdout = stdout
+	w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := io.ReadAll(r)
+	if actual := string(out); actual != "\x1b]52;c;b2s=\a" {
+		t.Errorf("not valid OSC 52 on stdout: %q", actual)
+	}
+
+	var calls []string
+	c := XClipClipboard()
+	c.Exec = func(name string, args []string, stdin string) (string, error) {
+		calls = append(calls, fmt.Sprintf("%s %s <%q", name, strings.Join(args, " "), stdin))
+		return "from system", nil
+	}
+	a.SetClipboard(c)
+	if err := a.Copy(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Paste(); err != nil {
+		t.Fatal(err)
+	}
+	if actual := string(a.GetText()); actual != "from system" {
+		t.Errorf("not valid paste: %q", actual)
+	}
+	if actual := strings.Join(calls, ";"); actual !=
+		`xclip -selection clipboard -in <" world";xclip -selection clipboard -out <""` {
+		t.Errorf("not valid commands: %q", actual)
+	}
+}
+
+func TestBulk(t *testing.T) {
+	var ta TextField
+	ta.SetWidth(20)
+	state := func() string {
+		ta.cursorInRect()
+		return string(ta.text[:ta.cursor]) + "|" + string(ta.text[ta.cursor:])
+	}
+	steps := []struct {
+		action func()
+		expect string
+	}{
+		{func() { ta.InsertString([]rune("hello world")) }, "hello world|"},
+		{func() { ta.Filter = NoWhitespace; ta.InsertString([]rune(" and all")) }, "hello worldandall|"},
+		{func() { ta.ReplaceRange(11, 17, []rune("!")) }, "hello world!|"},
+		{func() { ta.CursorPosition(0, 3); ta.DeleteRange(5, 1) }, "h| world!"},
+		{func() { ta.ReplaceRange(0, 5, []rune("yes")) }, "yes|ld!"},
+		{func() { ta.DeleteRange(-5, 100) }, "|"},
+		{func() { ta.Undo() }, "yes|ld!"},
+		{func() { ta.Undo(); ta.Undo(); ta.Undo() }, "hello worldandall|"},
+	}
+	for i, st := range steps {
+		st.action()
+		if actual := state(); actual != st.expect {
+			t.Fatalf("step %d:\n%s\n%s", i, actual, st.expect)
+		}
+	}
+}
+
+func TestKillRing(t *testing.T) {
+	var ta TextField
+	ta.SetWidth(40)
+	ta.SetText([]rune("one two three\nfour"))
+	state := func() string {
+		ta.cursorInRect()
+		return string(ta.text[:ta.cursor]) + "|" + string(ta.text[ta.cursor:])
+	}
+	steps := []struct {
+		action func()
+		expect string
+	}{
+		{func() { ta.CursorPosition(0, 4); ta.KillLineEnd() }, "one |\nfour"},
+		{func() { ta.KillLineEnd() }, "one |four"},
+		{func() { ta.Yank() }, "one two three\n|four"},
+		{func() { ta.CursorMoveLineEnd(); ta.KillWord() }, "one two three\n|"},
+		{func() { ta.KillWord() }, "one two |"},
+		{func() { ta.KillLineStart() }, "|"},
+		{func() { ta.Yank() }, "one two three\nfour|"},
+		{func() { ta.YankPop() }, "two three\n|"},
+		{func() { ta.YankPop() }, "one two three\nfour|"},
+		{func() { ta.CursorMoveLeft(); ta.YankPop() }, "one two three\nfou|r"},
+		{func() { ta.TransposeChars() }, "one two three\nforu|"},
+		{func() { ta.TransposeChars() }, "one two three\nfour|"},
+		{func() { ta.CursorPosition(0, 0); ta.CapitalizeWord() }, "One| two three\nfour"},
+		{func() { ta.UpcaseWord() }, "One TWO| three\nfour"},
+		{func() { ta.CursorMoveLineHome(); ta.DowncaseWord() }, "one| TWO three\nfour"},
+	}
+	for i, st := range steps {
+		st.action()
+		if actual := state(); actual != st.expect {
+			t.Fatalf("step %d:\n%q\n%q", i, actual, st.expect)
+		}
+	}
+}
+
+func TestVim(t *testing.T) {
+	tcs := []struct {
+		text   string
+		keys   string
+		expect string
+		mode   VimMode
+	}{
+		{"one two three", "w", "one |two three", VimNormal},
+		{"one two three", "2wb", "one |two three", VimNormal},
+		{"one two three", "e", "on|e two three", VimNormal},
+		{"one two three", "$", "one two thre|e", VimNormal},
+		{"one two three", "$0", "|one two three", VimNormal},
+		{"one two three", "ll", "on|e two three", VimNormal},
+		{"one two three", "100l", "one two thre|e", VimNormal},
+		{"ab\ncd\nef", "jl", "ab\nc|d\nef", VimNormal},
+		{"ab\ncd\nef", "Gk", "ab\n|cd\nef", VimNormal},
+		{"ab\ncd\nef", "G2gg", "ab\n|cd\nef", VimNormal},
+		{"one two three", "dw", "|two three", VimNormal},
+		{"one two three", "2dw", "|three", VimNormal},
+		{"one two three", "d2w", "|three", VimNormal},
+		{"one two three", "wde", "one | three", VimNormal},
+		{"one two three", "wd$", "one| ", VimNormal},
+		{"one two\nthree", "wdw", "one| \nthree", VimNormal},
+		{"one two three", "cwyes\x1b", "ye|s two three", VimNormal},
+		{"one two three", "wcwa", "one a| three", VimInsert},
+		{"ab\ncd\nef", "jdd", "ab\n|ef", VimNormal},
+		{"ab\ncd\nef", "Gdd", "ab\n|cd", VimNormal},
+		{"ab\ncd\nef", "2dd", "|ef", VimNormal},
+		{"ab\ncd\nef", "dj", "|ef", VimNormal},
+		{"one\ntwo", "dk", "|one\ntwo", VimNormal},
+		{"one\ntwo", "jdj", "one\n|two", VimNormal},
+		{"ab\ncd\nef", "3jx", "|b\ncd\nef", VimNormal},
+		{"ab\ncd\nef", "yyjp", "ab\ncd\n|ab\nef", VimNormal},
+		{"ab\ncd\nef", "yyjP", "ab\n|ab\ncd\nef", VimNormal},
+		{"one two", "dwwP", "twone| o", VimNormal},
+		{"one two", "ywP", "one| one two", VimNormal},
+		{"one two", "\"ayw\"byew\"aP", "one one| two", VimNormal},
+		{"abc", "xp", "b|ac", VimNormal},
+		{"abc", "$X", "a|c", VimNormal},
+		{"abc", "2x.", "|", VimNormal},
+		{"a b c d", "dw..", "|d", VimNormal},
+		{"abc", "ix\x1b", "|xabc", VimNormal},
+		{"abc", "ax\x1b", "a|xbc", VimNormal},
+		{"abc", "Ax\x1b", "abc|x", VimNormal},
+		{"  abc", "$Ix\x1b", "  |xabc", VimNormal},
+		{"abc", "oxy\x1b", "abc\nx|y", VimNormal},
+		{"abc", "Oxy\x1b", "x|y\nabc", VimNormal},
+		{"abc", "ix\x1bl.", "x|xabc", VimNormal},
+		{"abc", "ixy\x7f\x1b", "|xabc", VimNormal},
+		{"abc", "xxu", "|bc", VimNormal},
+		{"abc", "xxuu\x12", "|bc", VimNormal},
+		{"one two three", "wvlld", "one | three", VimNormal},
+		{"one two three", "wvey$p", "one two threetw|o", VimNormal},
+		{"one two three", "wvlc", "one |o three", VimInsert},
+		{"one two three", "wvl\x1bx", "one t|o three", VimNormal},
+		{"one two three", "dq", "|one two three", VimNormal},
+		{"XY XY", "vlx", "| XY", VimNormal},
+		{"XY XY", "$vhX", "XY| ", VimNormal},
+		{"one two", "ywwvep", "one one| ", VimNormal},
+		{"one two", "yewvP", "one on|ewo", VimNormal},
+		{"abc", "3ix\x1b", "xx|xabc", VimNormal},
+		{"abc", "2ix\x1b.", "xx|xxabc", VimNormal},
+		{"abc", "2oxy\x1b", "abc\nxy\nx|y", VimNormal},
+	}
+	for _, tc := range tcs {
+		var ta TextField
+		ta.SetText([]rune(tc.text))
+		ta.SetWidth(40)
+		ta.CursorPosition(0, 0)
+		v := NewVim(&ta)
+		for _, r := range tc.keys {
+			v.Key(r)
+		}
+		ta.cursorInRect()
+		actual := string(ta.text[:ta.cursor]) + "|" + string(ta.text[ta.cursor:])
+		if actual != tc.expect || v.Mode() != tc.mode {
+			t.Errorf("%q %q:\n%q %d\n%q %d", tc.text, tc.keys, actual, v.Mode(), tc.expect, tc.mode)
+		}
+	}
+
+	var ta TextField
+	ta.SetWidth(40)
+	if err := ta.SetInputMask("99-99"); err != nil {
+		t.Fatal(err)
+	}
+	ta.SetText([]rune("12-34"))
+	ta.CursorPosition(0, 0)
+	v := NewVim(&ta)
+	v.Key('l')
+	v.Key('l')
+	if actual := ta.GetCursor(); actual != 3 {
+		t.Errorf("cursor on literal of input mask: %d", actual)
+	}
+
+	var secret TextField
+	secret.SetWidth(40)
+	secret.SetMask('*')
+	secret.SetText([]rune("secret"))
+	v = NewVim(&secret)
+	for _, r := range "0yyvlxdd" {
+		v.Key(r)
+	}
+	if reg := v.Register('"'); reg != nil || len(secret.GetText()) != 0 {
+		t.Errorf("masked text is stored in register: %q", string(reg))
+	}
+}
+
 type fake interface {
 	CursorPosition(row, col uint)
 	CursorMoveUp()
 	CursorMoveDown()
 	CursorMoveLeft()
 	CursorMoveRight()
+	CursorMoveHome()
+	CursorMoveEnd()
+	CursorMoveLineHome()
+	CursorMoveLineEnd()
+	CursorPageDown()
+	CursorPageUp()
 
 	Insert(r rune)
 	KeyBackspace()
@@ -892,3 +2489,11 @@ func Benchmark(b *testing.B) {
 		}
 	})
 }
+func BenchmarkInsertString(b *testing.B) {
+	text := []rune(strings.Repeat("Lorem ipsum dolor sit amet.\n", 400))
+	for n := 0; n < b.N; n++ {
+		var ta TextField
+		ta.SetWidth(40)
+		ta.InsertString(text)
+	}
+}
@@ -0,0 +1,98 @@
+package tf
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrIncomplete is error of text, which can be valid after adding of
+// runes. Incomplete text is never rejected.
+var ErrIncomplete = errors.New("incomplete value")
+
+// Validator check full text of field
+type Validator interface {
+	Validate(text []rune) error
+}
+
+// ValidatorFunc is function implementation of Validator
+type ValidatorFunc func(text []rune) error
+
+// Validate implements Validator.
+func (f ValidatorFunc) Validate(text []rune) error {
+	return f(text)
+}
+
+var (
+	// UnsignedIntegerValidator accept text valid for strconv.ParseUint
+	UnsignedIntegerValidator Validator = ValidatorFunc(validUnsignedInteger)
+
+	// IntegerValidator accept text valid for strconv.ParseInt
+	IntegerValidator Validator = ValidatorFunc(validInteger)
+
+	// FloatValidator accept text valid for strconv.ParseFloat
+	FloatValidator Validator = ValidatorFunc(validFloat)
+)
+
+func validUnsignedInteger(text []rune) error {
+	s := string(text)
+	if s == "" {
+		return ErrIncomplete
+	}
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err
+}
+
+func validInteger(text []rune) error {
+	s := string(text)
+	if s == "" || s == "+" || s == "-" {
+		return ErrIncomplete
+	}
+	_, err := strconv.ParseInt(s, 10, 64)
+	return err
+}
+
+func validFloat(text []rune) error {
+	s := string(text)
+	_, err := strconv.ParseFloat(s, 64)
+	if err == nil || errors.Is(err, strconv.ErrRange) {
+		return err
+	}
+	// prefix of valid value
+	for _, tail := range []string{"0", "p0", "1p0"} {
+		if _, e := strconv.ParseFloat(s+tail, 64); e == nil {
+			return ErrIncomplete
+		}
+	}
+	word := strings.ToLower(strings.TrimLeft(s, "+-"))
+	if len(s)-len(word) <= 1 && word != "" &&
+		(strings.HasPrefix("infinity", word) || strings.HasPrefix("nan", word)) {
+		return ErrIncomplete
+	}
+	return err
+}
+
+// SetRejectInvalid set rejecting of edits with invalid text by
+// Validator. By default edits are not rejected and error is available
+// by GetError.
+func (t *TextField) SetRejectInvalid(reject bool) {
+	t.rejectInvalid = reject
+}
+
+// GetError return error of Validator for text or nil if text is valid.
+func (t TextField) GetError() error {
+	if t.Validator == nil {
+		return nil
+	}
+	return t.Validator.Validate(t.text)
+}
+
+// rejected return true if text after replacing of runes text[from:to]
+// by ins is rejected by Validator
+func (t *TextField) rejected(from, to int, ins []rune) bool {
+	if t.Validator == nil || !t.rejectInvalid {
+		return false
+	}
+	err := t.Validator.Validate(t.spliced(from, to, ins))
+	return err != nil && !errors.Is(err, ErrIncomplete)
+}
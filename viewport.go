@@ -0,0 +1,112 @@
+package tf
+
+// SetOverflow set runes for indicate hidden text at left and right side
+// of row in WrapNone mode. For example: '<' and '>' or '…' and '…'.
+// Zero rune is not drawn.
+func (t *TextField) SetOverflow(left, right rune) {
+	t.overflow.left = left
+	t.overflow.right = right
+}
+
+const (
+	hiddenLeft  uint8 = 1 << iota // row have hidden text at left side
+	hiddenRight                   // row have hidden text at right side
+)
+
+// viewport is horizontal window of rows for WrapNone mode
+type viewport struct {
+	enable      bool
+	offset      uint // first visible column
+	width       uint
+	left, right rune
+	hidden      []uint8 // hidden flags for each row
+}
+
+func newViewport(t *TextField, height uint) (v viewport) {
+	if t.state.wrap != WrapNone {
+		return
+	}
+	v = viewport{
+		enable: true,
+		width:  t.state.width,
+		left:   t.overflow.left,
+		right:  t.overflow.right,
+		hidden: make([]uint8, height),
+	}
+	if v.width < 4 {
+		// no place for indicators
+		v.left, v.right = 0, 0
+	}
+	v.offset = t.scroll(v)
+	return
+}
+
+// scroll return offset of viewport with visible cursor.
+func (t *TextField) scroll(v viewport) (offset uint) {
+	var lm, rm uint // margins for indicators
+	if v.left != 0 {
+		lm = 1
+	}
+	if v.right != 0 {
+		rm = 1
+	}
+	var end uint // column after the end of longest row
+	for p := range t.render {
+		if e := t.render[p].col + uint(t.render[p].w); end < e {
+			end = e
+		}
+	}
+	offset = t.state.offset
+	if end+rm < offset+v.width {
+		// text is shorter of viewport
+		offset = 0
+		if v.width < end+rm {
+			offset = end + rm - v.width
+		}
+	}
+	col := t.render[t.cursor].col
+	if offset+v.width < col+rm+1 {
+		offset = col + rm + 1 - v.width
+	}
+	if 0 < offset && col < offset+lm {
+		offset = 0
+		if lm < col {
+			offset = col - lm
+		}
+	}
+	t.state.offset = offset
+	return
+}
+
+// cell return position of cell in viewport. For partially visible
+// wide cell blank rune is returned.
+func (v *viewport) cell(p position, c Cell) (row, col uint, _ Cell, ok bool) {
+	blank := Cell{R: ' ', Selected: c.Selected}
+	if p.col < v.offset {
+		v.hidden[p.row] |= hiddenLeft
+		if v.offset < p.col+uint(p.w) {
+			return p.row, 0, blank, true
+		}
+		return
+	}
+	if v.offset+v.width < p.col+uint(p.w) {
+		v.hidden[p.row] |= hiddenRight
+		if p.col < v.offset+v.width {
+			return p.row, p.col - v.offset, blank, true
+		}
+		return
+	}
+	return p.row, p.col - v.offset, c, true
+}
+
+// indicators draw overflow indicators for rows with hidden text
+func (v viewport) indicators(drawer func(row, col uint, c Cell)) {
+	for row, h := range v.hidden {
+		if h&hiddenLeft != 0 && v.left != 0 {
+			drawer(uint(row), 0, Cell{R: v.left})
+		}
+		if h&hiddenRight != 0 && v.right != 0 {
+			drawer(uint(row), v.width-1, Cell{R: v.right})
+		}
+	}
+}
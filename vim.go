@@ -0,0 +1,673 @@
+package tf
+
+// VimMode is mode of Vim controller
+type VimMode uint8
+
+const (
+	VimNormal VimMode = iota
+	VimInsert
+	VimVisual
+)
+
+// Runes of special keys for Vim controller
+const (
+	RuneEscape    rune = 0x1b
+	RuneBackspace rune = 0x7f
+	RuneCtrlR     rune = 0x12
+)
+
+// register of Vim controller
+type register struct {
+	text     []rune
+	linewise bool // text is full lines without last new line
+}
+
+// Vim is modal controller of TextField with keys of vim editor:
+//
+//	motions:   h j k l w b e 0 $ gg G
+//	operators: d c y (with motion, doubled for lines or in visual mode)
+//	commands:  i a I A o O x X p P u Ctrl+R v .
+//
+// Commands may have count and register, for example `"a3dw`.
+//
+// Motions are computed from text instead of CursorMove methods, because
+// operators need target position without moving of cursor, and motions
+// of vim stop at end of logical line while CursorMoveLeft and
+// CursorMoveRight cross it. Cursor and selection are changed only by
+// SetCursor and SelectRange.
+type Vim struct {
+	t *TextField
+
+	mode      VimMode
+	keys      []rune // keys of not finished command
+	registers map[rune]register
+	anchor    int // begin of selection in visual mode
+
+	insert struct {
+		count int  // amount of insert repeats
+		start int  // cursor position at begin of insert
+		line  bool // each repeat is on new line
+	}
+
+	record    []rune // keys of current change
+	recording bool   // change is not finished in insert mode
+	last      []rune // keys of last change for repeat
+	replaying bool
+}
+
+// NewVim return Vim controller of field in normal mode.
+func NewVim(t *TextField) *Vim {
+	return &Vim{t: t, registers: map[rune]register{}}
+}
+
+// Mode return current mode.
+func (v *Vim) Mode() VimMode {
+	return v.mode
+}
+
+// Register return text of register. Unnamed register is '"'.
+func (v *Vim) Register(name rune) []rune {
+	return v.registers[name].text
+}
+
+// Key handle pressed key.
+func (v *Vim) Key(r rune) {
+	if v.recording {
+		v.record = append(v.record, r)
+	}
+	if v.mode == VimInsert {
+		v.insertKey(r)
+		return
+	}
+	if r == RuneEscape || (v.mode == VimVisual && r == 'v' && len(v.keys) == 0) {
+		v.keys = v.keys[:0]
+		v.setMode(VimNormal)
+		return
+	}
+	v.keys = append(v.keys, r)
+	if done := v.execute(v.keys); done {
+		v.keys = v.keys[:0]
+	}
+	if v.mode == VimNormal {
+		v.normalCursor()
+	}
+}
+
+func (v *Vim) insertKey(r rune) {
+	switch r {
+	case RuneEscape:
+		v.repeatInsert()
+		v.setMode(VimNormal)
+		if t := v.t; t.lineStart(t.GetCursor()) < t.GetCursor() {
+			t.CursorMoveLeft()
+		}
+		if v.recording {
+			v.recording = false
+			v.last = v.record
+		}
+	case RuneBackspace, '\b':
+		v.t.KeyBackspace()
+	default:
+		v.t.Insert(r)
+	}
+}
+
+// repeatInsert insert text of finished insert again for count of
+// insert command.
+func (v *Vim) repeatInsert() {
+	t := v.t
+	start, end := v.insert.start, t.GetCursor()
+	if v.insert.count < 2 || end < start || len(t.text) < start {
+		return
+	}
+	var text []rune
+	for k := 1; k < v.insert.count; k++ {
+		if v.insert.line {
+			text = append(text, '\n')
+		}
+		text = append(text, t.text[start:end]...)
+	}
+	t.InsertString(text)
+}
+
+func (v *Vim) setMode(mode VimMode) {
+	if v.mode == VimVisual && mode != VimVisual {
+		v.t.SelectNone()
+	}
+	if mode == VimVisual {
+		v.anchor = v.t.GetCursor()
+		v.t.SelectRange(v.anchor, v.anchor)
+	}
+	v.insert.count = 0
+	v.mode = mode
+}
+
+// setCursor move cursor to position. In visual mode selection is
+// extended to position.
+func (v *Vim) setCursor(pos int) {
+	if v.mode == VimVisual {
+		v.t.SelectRange(v.anchor, pos)
+		return
+	}
+	v.t.SetCursor(pos)
+}
+
+// visual return range of runes selected in visual mode
+func (v *Vim) visual() (from, to int) {
+	from, to = v.anchor, v.t.GetCursor()
+	if to < from {
+		from, to = to, from
+	}
+	return from, clusterEnd(v.t.text, to)
+}
+
+// normalCursor keep cursor on rune of line in normal mode
+func (v *Vim) normalCursor() {
+	t := v.t
+	pos := t.GetCursor()
+	if t.lineStart(pos) < pos && (pos == len(t.text) || t.text[pos] == '\n') {
+		v.setCursor(t.clusterBefore(pos))
+	}
+}
+
+// vimCount parse count from position and return count and position after it.
+// Result count is zero if count is not found.
+func vimCount(keys []rune, i int) (int, int) {
+	n := 0
+	for i < len(keys) && isDigit(keys[i]) && (n != 0 || keys[i] != '0') {
+		n = n*10 + int(keys[i]-'0')
+		i++
+	}
+	return n, i
+}
+
+// execute run command of keys. Result is false, if command is not
+// finished.
+func (v *Vim) execute(keys []rune) (done bool) {
+	t := v.t
+	cursor := t.GetCursor()
+	i, name := 0, '"'
+	if keys[0] == '"' {
+		if len(keys) < 3 {
+			return false
+		}
+		name, i = keys[1], 2
+	}
+	n1, i := vimCount(keys, i)
+	if i == len(keys) {
+		return false
+	}
+	key := keys[i]
+	i++
+	n := n1
+	if n == 0 {
+		n = 1
+	}
+	switch key {
+	case 'd', 'c', 'y':
+		if v.mode == VimVisual {
+			from, to := v.visual()
+			v.setMode(VimNormal)
+			v.operator(key, name, from, to, false)
+			return true
+		}
+		n2, j := vimCount(keys, i)
+		if j == len(keys) {
+			return false
+		}
+		if n2 != 0 {
+			n *= n2
+		}
+		motion := keys[j]
+		if motion == 'g' {
+			if j+1 == len(keys) {
+				return false
+			}
+			if keys[j+1] != 'g' {
+				return true
+			}
+		}
+		if motion == key {
+			if key != 'y' {
+				v.startChange(keys, key == 'c')
+			}
+			// lines
+			from := t.lineStart(cursor)
+			to := from
+			for k := 0; k < n; k++ {
+				to = t.lineEnd(to)
+				if k+1 < n && to < len(t.text) {
+					to++
+				}
+			}
+			v.operator(key, name, from, to, true)
+			return true
+		}
+		if key == 'c' && motion == 'w' && cursor < len(t.text) &&
+			t.class(t.text[cursor]) != blankClass && t.class(t.text[cursor]) != newlineClass {
+			// "cw" is same as "ce"
+			motion = 'e'
+		}
+		pos, linewise, inclusive, ok := v.motion(motion, n, n1 != 0 || n2 != 0)
+		if !ok {
+			return true
+		}
+		if key != 'y' {
+			v.startChange(keys, key == 'c')
+		}
+		from, to := cursor, pos
+		if to < from {
+			from, to = to, from
+		}
+		if inclusive {
+			to = clusterEnd(t.text, to)
+		}
+		if motion == 'w' && from < t.lineEnd(from) && t.lineEnd(from) < to {
+			// word motion does not join lines
+			to = t.lineEnd(from)
+		}
+		v.operator(key, name, from, to, linewise)
+		return true
+	case 'x', 'X':
+		if v.mode == VimVisual {
+			from, to := v.visual()
+			v.setMode(VimNormal)
+			v.operator('d', name, from, to, false)
+			return true
+		}
+		motion := 'l'
+		if key == 'X' {
+			motion = 'h'
+		}
+		pos, _, _, _ := v.motion(motion, n, true)
+		from, to := cursor, pos
+		if to < from {
+			from, to = to, from
+		}
+		if from < to {
+			v.startChange(keys, false)
+			v.operator('d', name, from, to, false)
+		}
+		return true
+	case 'p', 'P':
+		if v.mode == VimVisual {
+			from, to := v.visual()
+			v.setMode(VimNormal)
+			if reg, ok := v.registers[name]; ok {
+				v.replace(reg, n, from, to)
+			}
+			return true
+		}
+		if reg, ok := v.registers[name]; ok {
+			v.startChange(keys, false)
+			v.put(reg, n, key == 'p')
+			v.endChange()
+		}
+		return true
+	case 'i', 'a', 'I', 'A', 'o', 'O':
+		if v.mode == VimVisual {
+			return true
+		}
+		v.startChange(keys, true)
+		switch key {
+		case 'a':
+			if cursor < len(t.text) && t.text[cursor] != '\n' {
+				v.setCursor(clusterEnd(t.text, cursor))
+			}
+		case 'I':
+			v.setCursor(v.firstNonBlank(t.lineStart(cursor)))
+		case 'A':
+			v.setCursor(t.lineEnd(cursor))
+		case 'o':
+			pos := t.lineEnd(cursor)
+			v.setCursor(pos)
+			t.ReplaceRange(pos, pos, []rune{'\n'})
+		case 'O':
+			pos := t.lineStart(cursor)
+			v.setCursor(pos)
+			t.ReplaceRange(pos, pos, []rune{'\n'})
+			v.setCursor(pos)
+		}
+		v.setMode(VimInsert)
+		v.insert.count = n
+		v.insert.start = t.GetCursor()
+		v.insert.line = key == 'o' || key == 'O'
+		return true
+	case 'u':
+		v.setMode(VimNormal)
+		for k := 0; k < n; k++ {
+			t.Undo()
+		}
+		return true
+	case RuneCtrlR:
+		v.setMode(VimNormal)
+		for k := 0; k < n; k++ {
+			t.Redo()
+		}
+		return true
+	case 'v':
+		v.setMode(VimVisual)
+		return true
+	case '.':
+		if v.replaying || len(v.last) == 0 || v.mode != VimNormal {
+			return true
+		}
+		last := v.last
+		v.keys = v.keys[:0]
+		v.replaying = true
+		for k := 0; k < n; k++ {
+			for _, r := range last {
+				v.Key(r)
+			}
+		}
+		v.replaying = false
+		return true
+	case 'g':
+		if i == len(keys) {
+			return false
+		}
+		if keys[i] != 'g' {
+			return true
+		}
+	}
+	if pos, _, _, ok := v.motion(key, n, n1 != 0); ok {
+		v.setCursor(pos)
+	}
+	return true
+}
+
+// startChange start recording of change for repeat
+func (v *Vim) startChange(keys []rune, insert bool) {
+	if v.replaying || v.mode == VimVisual {
+		return
+	}
+	v.record = append([]rune{}, keys...)
+	v.recording = insert
+	if !insert {
+		v.last = v.record
+	}
+}
+
+// endChange finish change without insert mode
+func (v *Vim) endChange() {
+	v.recording = false
+}
+
+// motion return position after motion from cursor.
+func (v *Vim) motion(key rune, n int, counted bool) (pos int, linewise, inclusive, ok bool) {
+	t := v.t
+	pos = t.GetCursor()
+	switch key {
+	case 'h':
+		for k := 0; k < n && 0 < pos && t.text[pos-1] != '\n'; k++ {
+			pos = t.clusterBefore(pos)
+		}
+	case 'l':
+		for k := 0; k < n && pos < len(t.text) && t.text[pos] != '\n'; k++ {
+			pos = clusterEnd(t.text, pos)
+		}
+	case 'j', 'k':
+		line, column := v.line(pos), pos-t.lineStart(pos)
+		if key == 'j' {
+			line += n
+		} else {
+			line -= n
+		}
+		if line < 0 || v.line(len(t.text)) < line {
+			// motion out of text is failed
+			return pos, false, false, false
+		}
+		start := v.lineAt(line)
+		pos = start + column
+		if end := t.lineEnd(start); end < pos {
+			pos = end
+		}
+		pos = t.clusterStart(pos)
+		linewise = true
+	case 'w':
+		for k := 0; k < n; k++ {
+			pos = v.wordStart(pos)
+		}
+	case 'b':
+		for k := 0; k < n; k++ {
+			pos = v.wordBack(pos)
+		}
+	case 'e':
+		for k := 0; k < n; k++ {
+			pos = v.wordEnd(pos)
+		}
+		inclusive = true
+	case '0':
+		pos = t.lineStart(pos)
+	case '$':
+		pos = t.lineEnd(v.lineAt(v.line(pos) + n - 1))
+	case 'g', 'G':
+		line := 0
+		switch {
+		case counted:
+			line = n - 1
+		case key == 'G':
+			line = v.line(len(t.text))
+		}
+		pos = v.firstNonBlank(v.lineAt(line))
+		linewise = true
+	default:
+		return pos, false, false, false
+	}
+	return pos, linewise, inclusive, true
+}
+
+// line return index of line with position
+func (v *Vim) line(pos int) int {
+	line := 0
+	for _, r := range v.t.text[:pos] {
+		if r == '\n' {
+			line++
+		}
+	}
+	return line
+}
+
+// lineAt return begin of line with index. Index is limited by amount of
+// lines.
+func (v *Vim) lineAt(line int) int {
+	pos := 0
+	for ; 0 < line; line-- {
+		end := v.t.lineEnd(pos)
+		if end == len(v.t.text) {
+			break
+		}
+		pos = end + 1
+	}
+	return pos
+}
+
+func (v *Vim) firstNonBlank(pos int) int {
+	t := v.t
+	for pos < len(t.text) && t.class(t.text[pos]) == blankClass {
+		pos++
+	}
+	return pos
+}
+
+// wordStart return begin of next word
+func (v *Vim) wordStart(pos int) int {
+	t := v.t
+	if len(t.text) <= pos {
+		return len(t.text)
+	}
+	if c := t.class(t.text[pos]); c == ideographClass {
+		pos++
+	} else if c != blankClass && c != newlineClass {
+		for pos < len(t.text) && t.class(t.text[pos]) == c {
+			pos++
+		}
+	}
+	for pos < len(t.text) {
+		c := t.class(t.text[pos])
+		if c != blankClass && c != newlineClass {
+			break
+		}
+		pos++
+	}
+	return pos
+}
+
+// wordBack return begin of previous word
+func (v *Vim) wordBack(pos int) int {
+	t := v.t
+	for 0 < pos {
+		c := t.class(t.text[pos-1])
+		if c != blankClass && c != newlineClass {
+			break
+		}
+		pos--
+	}
+	if pos == 0 {
+		return 0
+	}
+	c := t.class(t.text[pos-1])
+	pos--
+	if c == ideographClass {
+		return pos
+	}
+	for 0 < pos && t.class(t.text[pos-1]) == c {
+		pos--
+	}
+	return pos
+}
+
+// wordEnd return last rune of next word end
+func (v *Vim) wordEnd(pos int) int {
+	t := v.t
+	if len(t.text) <= pos {
+		return pos
+	}
+	pos++
+	for pos < len(t.text) {
+		c := t.class(t.text[pos])
+		if c != blankClass && c != newlineClass {
+			break
+		}
+		pos++
+	}
+	if len(t.text) <= pos {
+		return t.clusterBefore(len(t.text))
+	}
+	c := t.class(t.text[pos])
+	if c != ideographClass {
+		for pos+1 < len(t.text) && t.class(t.text[pos+1]) == c {
+			pos++
+		}
+	}
+	return t.clusterStart(pos)
+}
+
+// store copy runes text[from:to] in register. Runes of masked field
+// are not stored.
+func (v *Vim) store(name rune, from, to int, linewise, yank bool) {
+	if v.t.masked() {
+		return
+	}
+	reg := register{text: append([]rune{}, v.t.text[from:to]...), linewise: linewise}
+	v.registers['"'] = reg
+	if name != '"' {
+		v.registers[name] = reg
+	}
+	if yank {
+		v.registers['0'] = reg
+	}
+}
+
+// operator apply operator to runes text[from:to]
+func (v *Vim) operator(key, name rune, from, to int, linewise bool) {
+	t := v.t
+	if linewise {
+		from = t.lineStart(from)
+		to = t.lineEnd(to)
+	}
+	v.store(name, from, to, linewise, key == 'y')
+	switch key {
+	case 'y':
+		v.setCursor(from)
+	case 'd':
+		if linewise {
+			// remove new line rune of lines
+			if to < len(t.text) {
+				to++
+			} else if 0 < from {
+				from--
+			}
+		}
+		v.setCursor(from)
+		t.DeleteRange(from, to)
+		if linewise {
+			pos := v.firstNonBlank(t.lineStart(from))
+			if from < pos && t.text[from] == '\n' {
+				pos = v.firstNonBlank(from + 1)
+			}
+			v.setCursor(pos)
+		}
+	case 'c':
+		v.setCursor(from)
+		t.DeleteRange(from, to)
+		v.setMode(VimInsert)
+	}
+}
+
+// repeated return text of register repeated n times
+func repeated(reg register, n int) []rune {
+	var text []rune
+	for k := 0; k < n; k++ {
+		if reg.linewise && 0 < k {
+			text = append(text, '\n')
+		}
+		text = append(text, reg.text...)
+	}
+	return text
+}
+
+// put insert register text after or before cursor
+func (v *Vim) put(reg register, n int, after bool) {
+	t := v.t
+	text := repeated(reg, n)
+	cursor := t.GetCursor()
+	switch {
+	case reg.linewise && after:
+		pos := t.lineEnd(cursor)
+		t.ReplaceRange(pos, pos, append([]rune{'\n'}, text...))
+		v.setCursor(v.firstNonBlank(pos + 1))
+	case reg.linewise:
+		pos := t.lineStart(cursor)
+		t.ReplaceRange(pos, pos, append(text, '\n'))
+		v.setCursor(v.firstNonBlank(pos))
+	default:
+		pos := cursor
+		if after && pos < len(t.text) && t.text[pos] != '\n' {
+			pos = clusterEnd(t.text, pos)
+		}
+		t.ReplaceRange(pos, pos, text)
+		v.setCursor(v.endOf(pos, text))
+	}
+}
+
+// replace put register text instead of runes text[from:to] of visual
+// mode. Replaced runes are stored in unnamed register.
+func (v *Vim) replace(reg register, n int, from, to int) {
+	t := v.t
+	text := repeated(reg, n)
+	if reg.linewise {
+		text = append(append([]rune{'\n'}, text...), '\n')
+	}
+	v.store('"', from, to, false, false)
+	t.ReplaceRange(from, to, text)
+	v.setCursor(v.endOf(from, text))
+}
+
+// endOf return position of last cluster of text inserted at position
+func (v *Vim) endOf(pos int, text []rune) int {
+	if len(text) == 0 {
+		return pos
+	}
+	return v.t.clusterBefore(pos + len(text))
+}
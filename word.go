@@ -0,0 +1,157 @@
+package tf
+
+import "unicode"
+
+// DefaultWordRune is default definition of word runes: letters, digits and
+// marks of any language.
+func DefaultWordRune(r rune) (word bool) {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// IdentifierWordRune is definition of word runes for source code:
+// letters, digits and underscore.
+func IdentifierWordRune(r rune) (word bool) {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+type wordClass uint8
+
+const (
+	blankClass     wordClass = iota // 0
+	newlineClass                    // 1
+	wordRuneClass                   // 2
+	ideographClass                  // 3
+	punctClass                      // 4
+)
+
+// class return word class of rune. Each ideograph is separate word.
+func (t *TextField) class(r rune) wordClass {
+	switch {
+	case r == '\n':
+		return newlineClass
+	case unicode.IsSpace(r):
+		return blankClass
+	case unicode.Is(unicode.Han, r):
+		return ideographClass
+	}
+	word := t.WordRune
+	if word == nil {
+		word = DefaultWordRune
+	}
+	if word(r) {
+		return wordRuneClass
+	}
+	return punctClass
+}
+
+// wordLeft return position of begin of word at left side of `pos`.
+func (t *TextField) wordLeft(pos int) int {
+	if len(t.text) < pos {
+		pos = len(t.text)
+	}
+	if pos == 0 {
+		return 0
+	}
+	if t.text[pos-1] == '\n' {
+		return pos - 1
+	}
+	for 0 < pos && t.class(t.text[pos-1]) == blankClass {
+		pos--
+	}
+	if pos == 0 || t.text[pos-1] == '\n' {
+		return pos
+	}
+	c := t.class(t.text[pos-1])
+	pos--
+	if c == ideographClass {
+		return pos
+	}
+	for 0 < pos && t.class(t.text[pos-1]) == c {
+		pos--
+	}
+	return pos
+}
+
+// wordRight return position of end of word at right side of `pos`.
+func (t *TextField) wordRight(pos int) int {
+	if len(t.text) <= pos {
+		return len(t.text)
+	}
+	if t.text[pos] == '\n' {
+		return pos + 1
+	}
+	for pos < len(t.text) && t.class(t.text[pos]) == blankClass {
+		pos++
+	}
+	if pos == len(t.text) || t.text[pos] == '\n' {
+		return pos
+	}
+	c := t.class(t.text[pos])
+	pos++
+	if c == ideographClass {
+		return pos
+	}
+	for pos < len(t.text) && t.class(t.text[pos]) == c {
+		pos++
+	}
+	return pos
+}
+
+// CursorMoveWordLeft move cursor to begin of word at left side.
+func (t *TextField) CursorMoveWordLeft() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	t.selection.active = false
+	t.cursor = t.wordLeft(t.cursor)
+}
+
+// CursorMoveWordRight move cursor to end of word at right side.
+func (t *TextField) CursorMoveWordRight() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	t.selection.active = false
+	t.cursor = t.wordRight(t.cursor)
+}
+
+// SelectMoveWordLeft extend selection to begin of word at left side.
+func (t *TextField) SelectMoveWordLeft() {
+	t.extend(t.CursorMoveWordLeft)
+}
+
+// SelectMoveWordRight extend selection to end of word at right side.
+func (t *TextField) SelectMoveWordRight() {
+	t.extend(t.CursorMoveWordRight)
+}
+
+// KeyBackspaceWord remove runes from begin of word at left side
+// to cursor.
+func (t *TextField) KeyBackspaceWord() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	if t.deleteSelection() {
+		return
+	}
+	if from := t.clusterStart(t.wordLeft(t.cursor)); from < t.cursor {
+		t.replace(from, t.cursor, nil, from, false)
+	}
+}
+
+// KeyDelWord remove runes from cursor to end of word at right side.
+func (t *TextField) KeyDelWord() {
+	// cursor correction
+	t.cursorInRect()
+	defer t.cursorInRect()
+	// action
+	if t.deleteSelection() {
+		return
+	}
+	if to := t.clusterAlign(t.wordRight(t.cursor)); t.cursor < to {
+		t.replace(t.cursor, to, nil, t.cursor, false)
+	}
+}